@@ -14,6 +14,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/mtraver/base91"
@@ -35,97 +36,265 @@ func Base_encode(dataString string) string {
 		return dataString
 	}
 	//------------------------------------------------------------
+	return string(AppendBase(nil, []byte(dataString)))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base_decode
+//------------------------------------------------------------
+
+func Base_decode(dataString string) (string, error) {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString, nil
+	}
+	//------------------------------------------------------------
+	dataBytes, err := AppendBase_decode(nil, []byte(dataString))
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(dataBytes), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base_encode_bytes
+//------------------------------------------------------------
+
+func Base_encode_bytes(dataBytes []byte) []byte {
+	//------------------------------------------------------------
+	return AppendBase([]byte{}, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base_decode_bytes
+//------------------------------------------------------------
+
+func Base_decode_bytes(dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	return AppendBase_decode([]byte{}, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase => appends the Base encoding of src to dst
+//------------------------------------------------------------
+
+func AppendBase(dst []byte, src []byte) []byte {
+	//------------------------------------------------------------
+	return defaultBaseEncoding.AppendEncode(dst, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase_decode => appends the decoded Base data of src to dst
+//------------------------------------------------------------
+
+func AppendBase_decode(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	return defaultBaseEncoding.AppendDecode(dst, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// BaseEncoding => Base encoding using a custom 85 character alphabet
+//------------------------------------------------------------
+
+type BaseEncoding struct {
+	alphabet  [85]byte
+	decodeMap [256]int
+}
+
+//------------------------------------------------------------
+
+var defaultBaseEncoding = mustNewBaseEncoding(BASE_CHARSET)
+
+//------------------------------------------------------------
+// NewBaseEncoding => alphabet must contain 85 unique printable ASCII characters (excluding space)
+//------------------------------------------------------------
+
+func NewBaseEncoding(alphabet string) (*BaseEncoding, error) {
+	//------------------------------------------------------------
+	if len(alphabet) != 85 {
+		return nil, fmt.Errorf("alphabet must contain 85 characters but contains %d", len(alphabet))
+	}
+	//------------------------------------------------------------
+	encoding := &BaseEncoding{}
+	//--------------------
+	for index := range encoding.decodeMap {
+		encoding.decodeMap[index] = -1
+	}
+	//------------------------------------------------------------
+	for index := 0; index < len(alphabet); index++ {
+		//--------------------
+		char := alphabet[index]
+		//--------------------
+		if char <= ' ' || char > '~' {
+			return nil, fmt.Errorf("alphabet contains invalid character %q at index %d", char, index)
+		}
+		//--------------------
+		if encoding.decodeMap[char] != -1 {
+			return nil, fmt.Errorf("alphabet contains duplicate character %q at index %d", char, index)
+		}
+		//--------------------
+		encoding.alphabet[index] = char
+		encoding.decodeMap[char] = index
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return encoding, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// mustNewBaseEncoding
+//------------------------------------------------------------
+
+func mustNewBaseEncoding(alphabet string) *BaseEncoding {
+	//------------------------------------------------------------
+	encoding, err := NewBaseEncoding(alphabet)
+	if err != nil {
+		panic(err)
+	}
+	//------------------------------------------------------------
+	return encoding
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BaseEncoding - Alphabet
+//------------------------------------------------------------
+
+func (encoding *BaseEncoding) Alphabet() string {
+	//------------------------------------------------------------
+	return string(encoding.alphabet[:])
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BaseEncoding - Encode
+//------------------------------------------------------------
+
+func (encoding *BaseEncoding) Encode(dataBytes []byte) []byte {
+	//------------------------------------------------------------
+	return encoding.AppendEncode([]byte{}, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BaseEncoding - Decode
+//------------------------------------------------------------
+
+func (encoding *BaseEncoding) Decode(dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	return encoding.AppendDecode([]byte{}, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BaseEncoding - AppendEncode => appends the encoding of src to dst
+//------------------------------------------------------------
+
+func (encoding *BaseEncoding) AppendEncode(dst []byte, src []byte) []byte {
+	//------------------------------------------------------------
+	if len(src) == 0 {
+		return dst
+	}
+	//------------------------------------------------------------
 	paddingLength := 0
-	if len(dataString)%4 > 0 {
-		paddingLength = 4 - len(dataString)%4
+	if len(src)%4 > 0 {
+		paddingLength = 4 - len(src)%4
 	}
 	//------------------------------------------------------------
-	outputLength := ((len(dataString) + paddingLength) / 4 * 5) - paddingLength
-	outputBytes := make([]byte, outputLength)
+	outputLength := ((len(src) + paddingLength) / 4 * 5) - paddingLength
+	//--------------------
+	dstLength := len(dst)
+	dst = slices.Grow(dst, outputLength)[:dstLength+outputLength]
+	//--------------------
+	outputBytes := dst[dstLength:]
 	outputIndex := 0
 	//------------------------------------------------------------
-	for dataIndex := 0; dataIndex < len(dataString); dataIndex += 4 {
+	for dataIndex := 0; dataIndex < len(src); dataIndex += 4 {
 		//---------------------------------------------------
 		var b0, b1, b2, b3 byte
 		//---------------------------------------------------
-		b0 = dataString[dataIndex]
-		if dataIndex+1 < len(dataString) {
-			b1 = dataString[dataIndex+1]
+		b0 = src[dataIndex]
+		if dataIndex+1 < len(src) {
+			b1 = src[dataIndex+1]
 		}
-		if dataIndex+2 < len(dataString) {
-			b2 = dataString[dataIndex+2]
+		if dataIndex+2 < len(src) {
+			b2 = src[dataIndex+2]
 		}
-		if dataIndex+3 < len(dataString) {
-			b3 = dataString[dataIndex+3]
+		if dataIndex+3 < len(src) {
+			b3 = src[dataIndex+3]
 		}
 		//---------------------------------------------------
 		charCodeSum := int(b0)<<24 | int(b1)<<16 | int(b2)<<8 | int(b3)
 		//---------------------------------------------------
-		if charCodeSum == 0 {
-			//---------------------------------------------------
-			OutputToByteSlice(&outputBytes, outputIndex, 33)
-			OutputToByteSlice(&outputBytes, outputIndex+1, 33)
-			OutputToByteSlice(&outputBytes, outputIndex+2, 33)
-			OutputToByteSlice(&outputBytes, outputIndex+3, 33)
-			OutputToByteSlice(&outputBytes, outputIndex+4, 33)
-			//---------------------------------------------------
-		} else {
-			//---------------------------------------------------
-			for subIndex := 4; subIndex >= 0; subIndex -= 1 {
-				value := charCodeSum % 85
-				charCodeSum = (charCodeSum - value) / 85
-				OutputToByteSlice(&outputBytes, outputIndex+subIndex, BASE_CHARSET[value])
-			}
-			//---------------------------------------------------
+		for subIndex := 4; subIndex >= 0; subIndex -= 1 {
+			value := charCodeSum % 85
+			charCodeSum = (charCodeSum - value) / 85
+			OutputToByteSlice(&outputBytes, outputIndex+subIndex, encoding.alphabet[value])
 		}
 		//---------------------------------------------------
 		outputIndex += 5
 		//---------------------------------------------------
 	}
 	//------------------------------------------------------------
-	return string(outputBytes)
+	return dst
 	//------------------------------------------------------------
 }
 
 //------------------------------------------------------------
-// Base_decode
+// BaseEncoding - AppendDecode => appends the decoded data of src to dst
 //------------------------------------------------------------
 
-func Base_decode(dataString string) (string, error) {
+func (encoding *BaseEncoding) AppendDecode(dst []byte, src []byte) ([]byte, error) {
 	//------------------------------------------------------------
-	if dataString == "" {
-		return dataString, nil
+	if len(src) == 0 {
+		return dst, nil
 	}
 	//------------------------------------------------------------
 	paddingLength := 0
-	if len(dataString)%5 > 0 {
-		paddingLength = 5 - len(dataString)%5
+	if len(src)%5 > 0 {
+		paddingLength = 5 - len(src)%5
 	}
 	//------------------------------------------------------------
-	outputLength := ((len(dataString) + paddingLength) / 5 * 4) - paddingLength
-	outputBytes := make([]byte, outputLength)
+	outputLength := ((len(src) + paddingLength) / 5 * 4) - paddingLength
+	//--------------------
+	dstLength := len(dst)
+	dst = slices.Grow(dst, outputLength)[:dstLength+outputLength]
+	//--------------------
+	outputBytes := dst[dstLength:]
 	outputIndex := 0
 	//------------------------------------------------------------
-	for dataIndex := 0; dataIndex < len(dataString); dataIndex += 5 {
+	for dataIndex := 0; dataIndex < len(src); dataIndex += 5 {
 		//---------------------------------------------------
 		b0, b1, b2, b3, b4 := 84, 84, 84, 84, 84
 		//---------------------------------------------------
-		b0 = strings.IndexByte(BASE_CHARSET, dataString[dataIndex])
-		if dataIndex+1 < len(dataString) {
-			b1 = strings.IndexByte(BASE_CHARSET, dataString[dataIndex+1])
+		b0 = encoding.decodeMap[src[dataIndex]]
+		if dataIndex+1 < len(src) {
+			b1 = encoding.decodeMap[src[dataIndex+1]]
 		}
-		if dataIndex+2 < len(dataString) {
-			b2 = strings.IndexByte(BASE_CHARSET, dataString[dataIndex+2])
+		if dataIndex+2 < len(src) {
+			b2 = encoding.decodeMap[src[dataIndex+2]]
 		}
-		if dataIndex+3 < len(dataString) {
-			b3 = strings.IndexByte(BASE_CHARSET, dataString[dataIndex+3])
+		if dataIndex+3 < len(src) {
+			b3 = encoding.decodeMap[src[dataIndex+3]]
 		}
-		if dataIndex+4 < len(dataString) {
-			b4 = strings.IndexByte(BASE_CHARSET, dataString[dataIndex+4])
+		if dataIndex+4 < len(src) {
+			b4 = encoding.decodeMap[src[dataIndex+4]]
 		}
 		//---------------------------------------------------
 		if b0 == -1 || b1 == -1 || b2 == -1 || b3 == -1 || b4 == -1 {
-			return "", fmt.Errorf("data contains one or more invalid characters")
+			return dst[:dstLength], fmt.Errorf("data contains one or more invalid characters")
 		}
 		//---------------------------------------------------
 		decodedChunk := 52200625*b0 + 614125*b1 + 7225*b2 + 85*b3 + b4
@@ -139,7 +308,7 @@ func Base_decode(dataString string) (string, error) {
 		//---------------------------------------------------
 	}
 	//------------------------------------------------------------
-	return string(outputBytes), nil
+	return dst, nil
 	//------------------------------------------------------------
 }
 
@@ -157,7 +326,7 @@ func Base64_encode(dataString string) string {
 		return dataString
 	}
 	//------------------------------------------------------------
-	return base64.StdEncoding.EncodeToString([]byte(dataString))
+	return string(AppendBase64(nil, []byte(dataString)))
 	//------------------------------------------------------------
 }
 
@@ -174,12 +343,56 @@ func Base64_decode(dataString string) (string, error) {
 		return dataString, err
 	}
 	//------------------------------------------------------------
-	dataBytes, err = base64.StdEncoding.DecodeString(dataString)
+	dataBytes, err = AppendBase64_decode(nil, []byte(dataString))
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base64_encode_bytes
+//------------------------------------------------------------
+
+func Base64_encode_bytes(dataBytes []byte) []byte {
+	//------------------------------------------------------------
+	return AppendBase64([]byte{}, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base64_decode_bytes
+//------------------------------------------------------------
+
+func Base64_decode_bytes(dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	return AppendBase64_decode([]byte{}, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase64 => appends the Base64 encoding of src to dst
+//------------------------------------------------------------
+
+func AppendBase64(dst []byte, src []byte) []byte {
+	//------------------------------------------------------------
+	return base64.StdEncoding.AppendEncode(dst, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase64_decode => appends the decoded Base64 data in src to dst
+//------------------------------------------------------------
+
+func AppendBase64_decode(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	dstLength := len(dst)
+	//------------------------------------------------------------
+	dst, err := base64.StdEncoding.AppendDecode(dst, src)
 	if err != nil {
-		dataBytes = []byte{}
+		return dst[:dstLength], err
 	}
 	//------------------------------------------------------------
-	return string(dataBytes), err
+	return dst, nil
 	//------------------------------------------------------------
 }
 
@@ -205,15 +418,7 @@ func Base64url_encode(dataString string) string {
 		return dataString
 	}
 	//------------------------------------------------------------
-	dataString = Base64_encode(dataString)
-	//------------------------------------------------------------
-	replacer := strings.NewReplacer(
-		"+", "-",
-		"/", "_",
-		"=", "",
-	)
-	//------------------------------------------------------------
-	return replacer.Replace(dataString)
+	return string(AppendBase64url(nil, []byte(dataString)))
 	//------------------------------------------------------------
 }
 
@@ -224,26 +429,80 @@ func Base64url_encode(dataString string) string {
 func Base64url_decode(dataString string) (string, error) {
 	//------------------------------------------------------------
 	var err error
+	var dataBytes []byte
 	//------------------------------------------------------------
 	if dataString == "" {
 		return dataString, err
 	}
 	//------------------------------------------------------------
-	replacer := strings.NewReplacer(
-		"-", "+",
-		"_", "/",
-	)
+	dataBytes, err = AppendBase64url_decode(nil, []byte(dataString))
 	//------------------------------------------------------------
-	dataString = replacer.Replace(dataString)
+	return string(dataBytes), err
 	//------------------------------------------------------------
-	switch len(dataString) % 4 { // Pad with trailing '='s
+}
+
+//------------------------------------------------------------
+// Base64url_encode_bytes
+//------------------------------------------------------------
+
+func Base64url_encode_bytes(dataBytes []byte) []byte {
+	//------------------------------------------------------------
+	return AppendBase64url([]byte{}, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base64url_decode_bytes
+//------------------------------------------------------------
+
+func Base64url_decode_bytes(dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	return AppendBase64url_decode([]byte{}, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase64url => appends the unpadded Base64url encoding of src to dst
+//------------------------------------------------------------
+
+func AppendBase64url(dst []byte, src []byte) []byte {
+	//------------------------------------------------------------
+	return base64.RawURLEncoding.AppendEncode(dst, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase64url_decode => appends the decoded Base64url data in src to dst
+//------------------------------------------------------------
+// accepts padded or unpadded input (and standard Base64 characters)
+//------------------------------------------------------------
+
+func AppendBase64url_decode(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	if len(src) == 0 {
+		return dst, nil
+	}
+	//------------------------------------------------------------
+	dataBytes := make([]byte, len(src), len(src)+2)
+	//--------------------
+	for index, value := range src {
+		switch value {
+		case '-':
+			value = '+'
+		case '_':
+			value = '/'
+		}
+		dataBytes[index] = value
+	}
+	//------------------------------------------------------------
+	switch len(dataBytes) % 4 { // Pad with trailing '='s
 	case 2:
-		dataString += "==" // 2 pad chars
+		dataBytes = append(dataBytes, '=', '=') // 2 pad chars
 	case 3:
-		dataString += "=" // 1 pad char
+		dataBytes = append(dataBytes, '=') // 1 pad char
 	}
 	//------------------------------------------------------------
-	return Base64_decode(dataString)
+	return AppendBase64_decode(dst, dataBytes)
 	//------------------------------------------------------------
 }
 
@@ -309,21 +568,7 @@ func Base91_encode(dataString string, escapeBool bool) string {
 		return dataString
 	}
 	//------------------------------------------------------------
-	dataString = base91.StdEncoding.EncodeToString([]byte(dataString))
-	//------------------------------------------------------------
-	if escapeBool {
-		//------------------------------------------------------------
-		replacer := strings.NewReplacer(
-			"\x22", "-q",
-			"\x24", "-d",
-			"\x60", "-g",
-		)
-		//------------------------------------------------------------
-		dataString = replacer.Replace(dataString)
-		//------------------------------------------------------------
-	}
-	//------------------------------------------------------------
-	return dataString
+	return string(AppendBase91(nil, []byte(dataString), escapeBool))
 	//------------------------------------------------------------
 }
 
@@ -340,24 +585,130 @@ func Base91_decode(dataString string, unescapeBool bool) (string, error) {
 		return dataString, err
 	}
 	//------------------------------------------------------------
+	dataBytes, err = AppendBase91_decode(nil, []byte(dataString), unescapeBool)
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base91_encode_bytes
+//------------------------------------------------------------
+
+func Base91_encode_bytes(dataBytes []byte, escapeBool bool) []byte {
+	//------------------------------------------------------------
+	return AppendBase91([]byte{}, dataBytes, escapeBool)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base91_decode_bytes
+//------------------------------------------------------------
+
+func Base91_decode_bytes(dataBytes []byte, unescapeBool bool) ([]byte, error) {
+	//------------------------------------------------------------
+	return AppendBase91_decode([]byte{}, dataBytes, unescapeBool)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase91 => appends the Base91 encoding of src to dst
+//------------------------------------------------------------
+// escapeBool => replaces \x22 \x24 \x60 with -q -d -g
+//------------------------------------------------------------
+
+func AppendBase91(dst []byte, src []byte, escapeBool bool) []byte {
+	//------------------------------------------------------------
+	if len(src) == 0 {
+		return dst
+	}
+	//------------------------------------------------------------
+	if !escapeBool {
+		//--------------------
+		dstLength := len(dst)
+		dst = slices.Grow(dst, base91.StdEncoding.EncodedLen(len(src)))
+		//--------------------
+		outputLength := base91.StdEncoding.Encode(dst[dstLength:cap(dst)], src)
+		//--------------------
+		return dst[:dstLength+outputLength]
+		//--------------------
+	}
+	//------------------------------------------------------------
+	encodedBytes := make([]byte, base91.StdEncoding.EncodedLen(len(src)))
+	encodedBytes = encodedBytes[:base91.StdEncoding.Encode(encodedBytes, src)]
+	//------------------------------------------------------------
+	for _, value := range encodedBytes {
+		switch value {
+		case '\x22':
+			dst = append(dst, '-', 'q')
+		case '\x24':
+			dst = append(dst, '-', 'd')
+		case '\x60':
+			dst = append(dst, '-', 'g')
+		default:
+			dst = append(dst, value)
+		}
+	}
+	//------------------------------------------------------------
+	return dst
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase91_decode => appends the decoded Base91 data in src to dst
+//------------------------------------------------------------
+
+func AppendBase91_decode(dst []byte, src []byte, unescapeBool bool) ([]byte, error) {
+	//------------------------------------------------------------
+	if len(src) == 0 {
+		return dst, nil
+	}
+	//------------------------------------------------------------
 	if unescapeBool {
-		//------------------------------------------------------------
-		replacer := strings.NewReplacer(
-			"-g", "\x60",
-			"-d", "\x24",
-			"-q", "\x22",
-		)
-		//------------------------------------------------------------
-		dataString = replacer.Replace(dataString)
-		//------------------------------------------------------------
-	}
-	//------------------------------------------------------------
-	dataBytes, err = base91.StdEncoding.DecodeString(dataString)
+		//--------------------
+		dataBytes := make([]byte, 0, len(src))
+		//--------------------
+		for index := 0; index < len(src); index++ {
+			//--------------------
+			if src[index] == '-' && index+1 < len(src) {
+				//--------------------
+				unescaped := true
+				//--------------------
+				switch src[index+1] {
+				case 'g':
+					dataBytes = append(dataBytes, '\x60')
+				case 'd':
+					dataBytes = append(dataBytes, '\x24')
+				case 'q':
+					dataBytes = append(dataBytes, '\x22')
+				default:
+					unescaped = false
+				}
+				//--------------------
+				if unescaped {
+					index++
+					continue
+				}
+				//--------------------
+			}
+			//--------------------
+			dataBytes = append(dataBytes, src[index])
+			//--------------------
+		}
+		//--------------------
+		src = dataBytes
+		//--------------------
+	}
+	//------------------------------------------------------------
+	dstLength := len(dst)
+	dst = slices.Grow(dst, base91.StdEncoding.DecodedLen(len(src)))
+	//--------------------
+	outputLength, err := base91.StdEncoding.Decode(dst[dstLength:cap(dst)], src)
 	if err != nil {
-		dataBytes = []byte{}
+		return dst[:dstLength], err
 	}
 	//------------------------------------------------------------
-	return string(dataBytes), err
+	return dst[:dstLength+outputLength], nil
 	//------------------------------------------------------------
 }
 
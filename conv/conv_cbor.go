@@ -0,0 +1,536 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"unicode/utf8"
+)
+
+//------------------------------------------------------------
+// CBOR (RFC 8949) for the same trees JSON_decode produces
+//------------------------------------------------------------
+
+const (
+	cborUnsignedInt byte = 0 << 5
+	cborNegativeInt byte = 1 << 5
+	cborByteString  byte = 2 << 5
+	cborTextString  byte = 3 << 5
+	cborArray       byte = 4 << 5
+	cborMap         byte = 5 << 5
+	cborTag         byte = 6 << 5
+	cborSimple      byte = 7 << 5
+)
+
+//------------------------------------------------------------
+
+type cborDecoder struct {
+	data   []byte
+	offset int
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// CBOR_Marshal => encodes input as CBOR, map keys are written in sorted order
+// and whole numbers are written as integers
+//------------------------------------------------------------
+
+func CBOR_Marshal(input interface{}) ([]byte, error) {
+	//------------------------------------------------------------
+	return appendCBOR(nil, input, 0)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CBOR_Unmarshal => decodes CBOR into map[string]any / []any trees
+//
+// integers are decoded as int64 (uint64 above math.MaxInt64), floats as float64,
+// byte strings as []byte and tags are ignored
+//------------------------------------------------------------
+
+func CBOR_Unmarshal(data []byte) (interface{}, error) {
+	//------------------------------------------------------------
+	decoder := cborDecoder{data: data}
+	//------------------------------------------------------------
+	value, err := decoder.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	if decoder.offset != len(data) {
+		return nil, decoder.error("unexpected data after top-level value")
+	}
+	//------------------------------------------------------------
+	return value, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// appendCBOR
+//------------------------------------------------------------
+
+func appendCBOR(dst []byte, input any, depth int) ([]byte, error) {
+	//------------------------------------------------------------
+	var err error
+	//------------------------------------------------------------
+	if depth > maxTreeDepth {
+		return dst, errors.New("cbor: maximum nesting depth exceeded")
+	}
+	//------------------------------------------------------------
+	switch value := input.(type) {
+	case nil:
+		return append(dst, cborSimple|22), nil
+	case bool:
+		if value {
+			return append(dst, cborSimple|21), nil
+		}
+		return append(dst, cborSimple|20), nil
+	case string:
+		return append(appendCBORHead(dst, cborTextString, uint64(len(value))), value...), nil
+	case []byte:
+		return append(appendCBORHead(dst, cborByteString, uint64(len(value))), value...), nil
+	case int:
+		return appendCBORInt(dst, int64(value)), nil
+	case int8:
+		return appendCBORInt(dst, int64(value)), nil
+	case int16:
+		return appendCBORInt(dst, int64(value)), nil
+	case int32:
+		return appendCBORInt(dst, int64(value)), nil
+	case int64:
+		return appendCBORInt(dst, value), nil
+	case uint:
+		return appendCBORHead(dst, cborUnsignedInt, uint64(value)), nil
+	case uint8:
+		return appendCBORHead(dst, cborUnsignedInt, uint64(value)), nil
+	case uint16:
+		return appendCBORHead(dst, cborUnsignedInt, uint64(value)), nil
+	case uint32:
+		return appendCBORHead(dst, cborUnsignedInt, uint64(value)), nil
+	case uint64:
+		return appendCBORHead(dst, cborUnsignedInt, value), nil
+	case float32:
+		return appendCBORFloat(dst, float64(value)), nil
+	case float64:
+		return appendCBORFloat(dst, value), nil
+	case json.Number:
+		//--------------------
+		number, err := treeNumber(value)
+		if err != nil {
+			return dst, err
+		}
+		//--------------------
+		return appendCBOR(dst, number, depth)
+		//--------------------
+	case []any:
+		//--------------------
+		dst = appendCBORHead(dst, cborArray, uint64(len(value)))
+		//--------------------
+		for _, element := range value {
+			if dst, err = appendCBOR(dst, element, depth+1); err != nil {
+				return dst, err
+			}
+		}
+		//--------------------
+		return dst, nil
+		//--------------------
+	case map[string]any:
+		//--------------------
+		dst = appendCBORHead(dst, cborMap, uint64(len(value)))
+		//--------------------
+		for _, key := range sortedKeys(value) {
+			dst = append(appendCBORHead(dst, cborTextString, uint64(len(key))), key...)
+			if dst, err = appendCBOR(dst, value[key], depth+1); err != nil {
+				return dst, err
+			}
+		}
+		//--------------------
+		return dst, nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	treeInput, err := treeValue(input)
+	if err != nil {
+		return dst, err
+	}
+	//------------------------------------------------------------
+	return appendCBOR(dst, treeInput, depth)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendCBORHead => major type with argument using the shortest form
+//------------------------------------------------------------
+
+func appendCBORHead(dst []byte, majorType byte, argument uint64) []byte {
+	//------------------------------------------------------------
+	switch {
+	case argument < 24:
+		return append(dst, majorType|byte(argument))
+	case argument <= math.MaxUint8:
+		return append(dst, majorType|24, byte(argument))
+	case argument <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, majorType|25), uint16(argument))
+	case argument <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, majorType|26), uint32(argument))
+	}
+	//------------------------------------------------------------
+	return binary.BigEndian.AppendUint64(append(dst, majorType|27), argument)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendCBORInt
+//------------------------------------------------------------
+
+func appendCBORInt(dst []byte, value int64) []byte {
+	//------------------------------------------------------------
+	if value < 0 {
+		return appendCBORHead(dst, cborNegativeInt, uint64(-1-value))
+	}
+	//------------------------------------------------------------
+	return appendCBORHead(dst, cborUnsignedInt, uint64(value))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendCBORFloat
+//------------------------------------------------------------
+
+func appendCBORFloat(dst []byte, value float64) []byte {
+	//------------------------------------------------------------
+	if intValue, ok := floatAsInt(value); ok {
+		return appendCBORInt(dst, intValue)
+	}
+	//------------------------------------------------------------
+	if float64(float32(value)) == value || math.IsInf(value, 0) {
+		return binary.BigEndian.AppendUint32(append(dst, cborSimple|26), math.Float32bits(float32(value)))
+	}
+	//------------------------------------------------------------
+	return binary.BigEndian.AppendUint64(append(dst, cborSimple|27), math.Float64bits(value))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// cborDecoder - error
+//------------------------------------------------------------
+
+func (decoder *cborDecoder) error(reason string) error {
+	//------------------------------------------------------------
+	return DecodeError{Encoding: "cbor", Offset: int64(decoder.offset), Reason: reason}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cborDecoder - read => returns the next n bytes
+//------------------------------------------------------------
+
+func (decoder *cborDecoder) read(n uint64) ([]byte, error) {
+	//------------------------------------------------------------
+	if n > uint64(len(decoder.data)-decoder.offset) {
+		return nil, decoder.error("unexpected end of data")
+	}
+	//------------------------------------------------------------
+	dataBytes := decoder.data[decoder.offset : decoder.offset+int(n)]
+	decoder.offset += int(n)
+	//------------------------------------------------------------
+	return dataBytes, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cborDecoder - head => returns major type, additional info and argument
+//------------------------------------------------------------
+
+func (decoder *cborDecoder) head() (byte, byte, uint64, error) {
+	//------------------------------------------------------------
+	headBytes, err := decoder.read(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	//------------------------------------------------------------
+	majorType := headBytes[0] & 0xe0
+	additionalInfo := headBytes[0] & 0x1f
+	//------------------------------------------------------------
+	var argumentBytes []byte
+	//--------------------
+	switch {
+	case additionalInfo < 24 || additionalInfo == 31:
+		return majorType, additionalInfo, uint64(additionalInfo), nil
+	case additionalInfo <= 27:
+		argumentBytes, err = decoder.read(1 << (additionalInfo - 24))
+	default:
+		decoder.offset--
+		return 0, 0, 0, decoder.error("reserved additional information")
+	}
+	//------------------------------------------------------------
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	//------------------------------------------------------------
+	var argument uint64
+	//--------------------
+	for _, argumentByte := range argumentBytes {
+		argument = argument<<8 | uint64(argumentByte)
+	}
+	//------------------------------------------------------------
+	return majorType, additionalInfo, argument, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cborDecoder - decode
+//------------------------------------------------------------
+
+func (decoder *cborDecoder) decode(depth int) (any, error) {
+	//------------------------------------------------------------
+	if depth > maxTreeDepth {
+		return nil, decoder.error("maximum nesting depth exceeded")
+	}
+	//------------------------------------------------------------
+	headOffset := decoder.offset
+	//--------------------
+	majorType, additionalInfo, argument, err := decoder.head()
+	if err != nil {
+		return nil, err
+	}
+	//--------------------
+	indefiniteBool := additionalInfo == 31
+	//--------------------
+	if indefiniteBool && (majorType == cborUnsignedInt || majorType == cborNegativeInt || majorType == cborTag) {
+		decoder.offset = headOffset
+		return nil, decoder.error("invalid indefinite length")
+	}
+	//------------------------------------------------------------
+	switch majorType {
+	case cborUnsignedInt:
+		//--------------------
+		if argument > math.MaxInt64 {
+			return argument, nil
+		}
+		//--------------------
+		return int64(argument), nil
+		//--------------------
+	case cborNegativeInt:
+		//--------------------
+		if argument > math.MaxInt64 {
+			decoder.offset = headOffset
+			return nil, decoder.error("negative integer out of range")
+		}
+		//--------------------
+		return -1 - int64(argument), nil
+		//--------------------
+	case cborByteString, cborTextString:
+		//--------------------
+		var stringBytes []byte
+		//--------------------
+		if indefiniteBool {
+			stringBytes, err = decoder.decodeChunks(majorType)
+		} else {
+			stringBytes, err = decoder.read(argument)
+		}
+		//--------------------
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		if majorType == cborByteString {
+			return append([]byte{}, stringBytes...), nil
+		}
+		//--------------------
+		if !utf8.Valid(stringBytes) {
+			decoder.offset = headOffset
+			return nil, decoder.error("invalid UTF-8 in text string")
+		}
+		//--------------------
+		return string(stringBytes), nil
+		//--------------------
+	case cborArray:
+		//--------------------
+		array := []any{}
+		//--------------------
+		for index := uint64(0); indefiniteBool || index < argument; index++ {
+			//--------------------
+			if indefiniteBool && decoder.breakNext() {
+				break
+			}
+			//--------------------
+			element, err := decoder.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			//--------------------
+			array = append(array, element)
+			//--------------------
+		}
+		//--------------------
+		return array, nil
+		//--------------------
+	case cborMap:
+		//--------------------
+		valueMap := map[string]any{}
+		//--------------------
+		for index := uint64(0); indefiniteBool || index < argument; index++ {
+			//--------------------
+			if indefiniteBool && decoder.breakNext() {
+				break
+			}
+			//--------------------
+			keyOffset := decoder.offset
+			//--------------------
+			key, err := decoder.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			//--------------------
+			keyString, ok := key.(string)
+			if !ok {
+				decoder.offset = keyOffset
+				return nil, decoder.error("map keys must be text strings")
+			}
+			//--------------------
+			if valueMap[keyString], err = decoder.decode(depth + 1); err != nil {
+				return nil, err
+			}
+			//--------------------
+		}
+		//--------------------
+		return valueMap, nil
+		//--------------------
+	case cborTag:
+		//--------------------
+		return decoder.decode(depth + 1)
+		//--------------------
+	}
+	//------------------------------------------------------------
+	switch additionalInfo {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfToFloat64(uint16(argument)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(argument))), nil
+	case 27:
+		return math.Float64frombits(argument), nil
+	}
+	//------------------------------------------------------------
+	decoder.offset = headOffset
+	//------------------------------------------------------------
+	if additionalInfo == 31 {
+		return nil, decoder.error("unexpected break")
+	}
+	//------------------------------------------------------------
+	return nil, decoder.error("unsupported simple value")
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cborDecoder - breakNext => consumes the break code if it is next
+//------------------------------------------------------------
+
+func (decoder *cborDecoder) breakNext() bool {
+	//------------------------------------------------------------
+	if decoder.offset < len(decoder.data) && decoder.data[decoder.offset] == cborSimple|31 {
+		decoder.offset++
+		return true
+	}
+	//------------------------------------------------------------
+	return false
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cborDecoder - decodeChunks => indefinite length byte / text strings
+//------------------------------------------------------------
+
+func (decoder *cborDecoder) decodeChunks(majorType byte) ([]byte, error) {
+	//------------------------------------------------------------
+	stringBytes := []byte{}
+	//------------------------------------------------------------
+	for !decoder.breakNext() {
+		//--------------------
+		chunkOffset := decoder.offset
+		//--------------------
+		chunkType, additionalInfo, argument, err := decoder.head()
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		if chunkType != majorType || additionalInfo == 31 {
+			decoder.offset = chunkOffset
+			return nil, decoder.error("invalid chunk in indefinite length string")
+		}
+		//--------------------
+		chunkBytes, err := decoder.read(argument)
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		stringBytes = append(stringBytes, chunkBytes...)
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return stringBytes, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// halfToFloat64 => IEEE 754 half precision
+//------------------------------------------------------------
+
+func halfToFloat64(half uint16) float64 {
+	//------------------------------------------------------------
+	exponent := int(half>>10) & 0x1f
+	mantissa := float64(half & 0x3ff)
+	//------------------------------------------------------------
+	var value float64
+	//--------------------
+	switch exponent {
+	case 0:
+		value = math.Ldexp(mantissa, -24)
+	case 31:
+		if mantissa == 0 {
+			value = math.Inf(1)
+		} else {
+			value = math.NaN()
+		}
+	default:
+		value = math.Ldexp(mantissa+1024, exponent-25)
+	}
+	//------------------------------------------------------------
+	if half&0x8000 != 0 {
+		return -value
+	}
+	//------------------------------------------------------------
+	return value
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
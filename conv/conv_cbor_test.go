@@ -0,0 +1,175 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// CBOR_Marshal
+//------------------------------------------------------------
+
+func TestCBOR_Marshal(t *testing.T) {
+	//------------------------------------------------------------
+	// RFC 8949 appendix A
+	testCases := []struct {
+		input       any
+		expectedHex string
+	}{
+		{0, "00"},
+		{23, "17"},
+		{24, "1818"},
+		{1000, "1903e8"},
+		{uint64(18446744073709551615), "1bffffffffffffffff"},
+		{-1, "20"},
+		{-1000, "3903e7"},
+		{float64(1), "01"},
+		{1.5, "fa3fc00000"},
+		{1.1, "fb3ff199999999999a"},
+		{math.Inf(1), "fa7f800000"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{"", "60"},
+		{"IETF", "6449455446"},
+		{"ü", "62c3bc"},
+		{[]any{}, "80"},
+		{[]any{1, []any{2, 3}, []any{4, 5}}, "8301820203820405"},
+		{map[string]any{"a": 1, "b": []any{2, 3}}, "a26161016162820203"},
+		{[]string{"a", "b"}, "8261616162"},
+		{jsonTestStruct{ID: 1, Name: "x"}, "a262696401646e616d656178"},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		resultBytes, err := CBOR_Marshal(testCase.input)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%#v) %v", testCase.input, err)
+		} else if hex.EncodeToString(resultBytes) != testCase.expectedHex {
+			t.Errorf("(%#v) resultHex = %q but should = %q", testCase.input, hex.EncodeToString(resultBytes), testCase.expectedHex)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CBOR_Unmarshal
+//------------------------------------------------------------
+
+func TestCBOR_Unmarshal(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		dataHex        string
+		expectedString string
+	}{
+		{"1bffffffffffffffff", "18446744073709551615"},
+		{"3b7fffffffffffffff", "-9223372036854775808"},
+		{"f93c00", "1"},
+		{"f9c400", "-4"},
+		{"f90001", "5.960464477539063e-8"},
+		{"c074323031332d30332d32315432303a30343a30305a", `"2013-03-21T20:04:00Z"`},
+		{"5f42010243030405ff", `"AQIDBAU="`},
+		{"7f657374726561646d696e67ff", `"streaming"`},
+		{"9f018202039f0405ffff", `[1,[2,3],[4,5]]`},
+		{"bf6346756ef563416d7421ff", `{"Amt":-2,"Fun":true}`},
+		{"a26161016162820203", `{"a":1,"b":[2,3]}`},
+		{"f7", `null`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		dataBytes, _ := hex.DecodeString(testCase.dataHex)
+		//--------------------
+		value, err := CBOR_Unmarshal(dataBytes)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%s) %v", testCase.dataHex, err)
+			continue
+		}
+		//--------------------
+		resultString, _ := JSON_encode(value)
+		//--------------------
+		if resultString != testCase.expectedString {
+			t.Errorf("(%s) resultString = %q but should = %q", testCase.dataHex, resultString, testCase.expectedString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	errorCases := []struct {
+		dataHex        string
+		expectedOffset int64
+	}{
+		{"", 0},
+		{"1903", 1},
+		{"6449455446ff", 5},
+		{"a10102", 1},
+		{"62c328", 0},
+		{"ff", 0},
+		{"1f", 0},
+		{"3bffffffffffffffff", 0},
+		{"9f01", 2},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range errorCases {
+		//--------------------
+		var decodeError DecodeError
+		//--------------------
+		dataBytes, _ := hex.DecodeString(testCase.dataHex)
+		//--------------------
+		_, err := CBOR_Unmarshal(dataBytes)
+		//--------------------
+		if !errors.As(err, &decodeError) {
+			t.Errorf("(%s) err = %v but should be a DecodeError", testCase.dataHex, err)
+		} else if decodeError.Offset != testCase.expectedOffset {
+			t.Errorf("(%s) offset = %d but should = %d (%v)", testCase.dataHex, decodeError.Offset, testCase.expectedOffset, err)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CBOR_Marshal / CBOR_Unmarshal
+//------------------------------------------------------------
+
+func TestCBOR_roundTrip(t *testing.T) {
+	//------------------------------------------------------------
+	jsonString := `{"array":[1,-2,3.25,"x",null,true,false,{}],"big":9007199254740993,"nested":{"a":{"b":[]}},"text":"ABC <> £ 🐧"}`
+	//------------------------------------------------------------
+	input, err := JSON_DecodeNumber[any](jsonString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	cborBytes, err := CBOR_Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	value, err := CBOR_Unmarshal(cborBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	resultString, _ := JSON_encode(value)
+	//--------------------
+	if resultString != jsonString {
+		t.Errorf("resultString = %q but should = %q", resultString, jsonString)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
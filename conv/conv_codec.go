@@ -0,0 +1,140 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+type Codec interface {
+	Encode(dataBytes []byte) []byte
+	Decode(dataBytes []byte) ([]byte, error)
+}
+
+//------------------------------------------------------------
+
+// CodecFuncs => allows a pair of functions to be used as a Codec
+type CodecFuncs struct {
+	EncodeFunc func(dataBytes []byte) []byte
+	DecodeFunc func(dataBytes []byte) ([]byte, error)
+}
+
+func (codec CodecFuncs) Encode(dataBytes []byte) []byte {
+	return codec.EncodeFunc(dataBytes)
+}
+
+func (codec CodecFuncs) Decode(dataBytes []byte) ([]byte, error) {
+	return codec.DecodeFunc(dataBytes)
+}
+
+//------------------------------------------------------------
+
+var codecRegistry = struct {
+	sync.RWMutex
+	codecs map[string]Codec
+}{codecs: map[string]Codec{}}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// init
+//------------------------------------------------------------
+
+func init() {
+	//------------------------------------------------------------
+	RegisterCodec("base", CodecFuncs{Base_encode_bytes, Base_decode_bytes})
+	//--------------------
+	RegisterCodec("base64", CodecFuncs{Base64_encode_bytes, Base64_decode_bytes})
+	RegisterCodec("base64url", CodecFuncs{Base64url_encode_bytes, Base64url_decode_bytes})
+	//--------------------
+	RegisterCodec("base91", CodecFuncs{
+		func(dataBytes []byte) []byte { return Base91_encode_bytes(dataBytes, false) },
+		func(dataBytes []byte) ([]byte, error) { return Base91_decode_bytes(dataBytes, false) },
+	})
+	RegisterCodec("base91-escaped", CodecFuncs{
+		func(dataBytes []byte) []byte { return Base91_encode_bytes(dataBytes, true) },
+		func(dataBytes []byte) ([]byte, error) { return Base91_decode_bytes(dataBytes, true) },
+	})
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// RegisterCodec => adds (or replaces) a codec (names are case insensitive)
+//------------------------------------------------------------
+
+func RegisterCodec(name string, codec Codec) error {
+	//------------------------------------------------------------
+	name = strings.ToLower(strings.TrimSpace(name))
+	//------------------------------------------------------------
+	if name == "" {
+		return errors.New("codec name cannot be blank")
+	}
+	//--------------------
+	if codec == nil {
+		return errors.New("codec cannot be nil")
+	}
+	//------------------------------------------------------------
+	codecRegistry.Lock()
+	defer codecRegistry.Unlock()
+	//------------------------------------------------------------
+	codecRegistry.codecs[name] = codec
+	//------------------------------------------------------------
+	return nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// LookupCodec
+//------------------------------------------------------------
+
+func LookupCodec(name string) (Codec, bool) {
+	//------------------------------------------------------------
+	codecRegistry.RLock()
+	defer codecRegistry.RUnlock()
+	//------------------------------------------------------------
+	codec, exists := codecRegistry.codecs[strings.ToLower(strings.TrimSpace(name))]
+	//------------------------------------------------------------
+	return codec, exists
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CodecNames => sorted names of all registered codecs
+//------------------------------------------------------------
+
+func CodecNames() []string {
+	//------------------------------------------------------------
+	codecRegistry.RLock()
+	defer codecRegistry.RUnlock()
+	//------------------------------------------------------------
+	names := make([]string, 0, len(codecRegistry.codecs))
+	//--------------------
+	for name := range codecRegistry.codecs {
+		names = append(names, name)
+	}
+	//--------------------
+	sort.Strings(names)
+	//------------------------------------------------------------
+	return names
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,113 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// LookupCodec
+//------------------------------------------------------------
+
+func TestLookupCodec(t *testing.T) {
+	//------------------------------------------------------------
+	dataString := "ABC <> &quot; £ 日本語\U0001f427"
+	//------------------------------------------------------------
+	testCases := []struct {
+		name           string
+		expectedString string
+	}{
+		{"base", Base_encode(dataString)},
+		{"base64", Base64_encode(dataString)},
+		{"BASE64URL", Base64url_encode(dataString)},
+		{"base91", Base91_encode(dataString, false)},
+		{"base91-escaped", Base91_encode(dataString, true)},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		codec, exists := LookupCodec(testCase.name)
+		//--------------------
+		if !exists {
+			t.Errorf("codec %q should exist", testCase.name)
+			continue
+		}
+		//--------------------
+		encodedBytes := codec.Encode([]byte(dataString))
+		//--------------------
+		if string(encodedBytes) != testCase.expectedString {
+			t.Errorf("(%q) encoded = %q but should = %q", testCase.name, string(encodedBytes), testCase.expectedString)
+		}
+		//--------------------
+		decodedBytes, err := codec.Decode(encodedBytes)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if string(decodedBytes) != dataString {
+			t.Errorf("(%q) decoded = %q but should = %q", testCase.name, string(decodedBytes), dataString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if _, exists := LookupCodec("unknown"); exists {
+		t.Error("codec \"unknown\" should not exist")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// RegisterCodec
+//------------------------------------------------------------
+
+func TestRegisterCodec(t *testing.T) {
+	//------------------------------------------------------------
+	reverse := func(dataBytes []byte) []byte {
+		reversedBytes := bytes.Clone(dataBytes)
+		for i, j := 0, len(reversedBytes)-1; i < j; i, j = i+1, j-1 {
+			reversedBytes[i], reversedBytes[j] = reversedBytes[j], reversedBytes[i]
+		}
+		return reversedBytes
+	}
+	//------------------------------------------------------------
+	err := RegisterCodec("Test-Reverse", CodecFuncs{reverse, func(dataBytes []byte) ([]byte, error) { return reverse(dataBytes), nil }})
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	codec, exists := LookupCodec("test-reverse")
+	//--------------------
+	if !exists {
+		t.Fatal("codec \"test-reverse\" should exist")
+	}
+	//--------------------
+	if string(codec.Encode([]byte("ABC"))) != "CBA" {
+		t.Errorf("encoded = %q but should = %q", string(codec.Encode([]byte("ABC"))), "CBA")
+	}
+	//------------------------------------------------------------
+	codecNames := CodecNames()
+	//--------------------
+	if !slices.Contains(codecNames, "test-reverse") || !slices.IsSorted(codecNames) {
+		t.Errorf("CodecNames() = %v", codecNames)
+	}
+	//------------------------------------------------------------
+	if RegisterCodec("", codec) == nil {
+		t.Error("blank codec name should return an error")
+	}
+	//--------------------
+	if RegisterCodec("nil", nil) == nil {
+		t.Error("nil codec should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
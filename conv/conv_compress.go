@@ -0,0 +1,277 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+)
+
+//------------------------------------------------------------
+
+// default maximum decompressed size (guards against zip bombs)
+const DECOMPRESS_MAX_SIZE int64 = 64 * 1024 * 1024
+
+//------------------------------------------------------------
+
+var ErrDecompressMaxSize = errors.New("decompressed data exceeds maximum size")
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Gzip_compress
+//------------------------------------------------------------
+
+func Gzip_compress(dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	var compressBuffer bytes.Buffer
+	//------------------------------------------------------------
+	return compress(&compressBuffer, gzip.NewWriter(&compressBuffer), dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Gzip_decompress => maxSize defaults to DECOMPRESS_MAX_SIZE
+//------------------------------------------------------------
+
+func Gzip_decompress(dataBytes []byte, maxSize ...int64) ([]byte, error) {
+	//------------------------------------------------------------
+	reader, err := gzip.NewReader(bytes.NewReader(dataBytes))
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	return decompress(reader, maxSize...)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Zlib_compress
+//------------------------------------------------------------
+
+func Zlib_compress(dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	var compressBuffer bytes.Buffer
+	//------------------------------------------------------------
+	return compress(&compressBuffer, zlib.NewWriter(&compressBuffer), dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Zlib_decompress => maxSize defaults to DECOMPRESS_MAX_SIZE
+//------------------------------------------------------------
+
+func Zlib_decompress(dataBytes []byte, maxSize ...int64) ([]byte, error) {
+	//------------------------------------------------------------
+	reader, err := zlib.NewReader(bytes.NewReader(dataBytes))
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	return decompress(reader, maxSize...)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Deflate_compress => raw deflate (RFC 1951) without any header
+//------------------------------------------------------------
+
+func Deflate_compress(dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	var compressBuffer bytes.Buffer
+	//------------------------------------------------------------
+	writer, err := flate.NewWriter(&compressBuffer, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	return compress(&compressBuffer, writer, dataBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Deflate_decompress => maxSize defaults to DECOMPRESS_MAX_SIZE
+//------------------------------------------------------------
+
+func Deflate_decompress(dataBytes []byte, maxSize ...int64) ([]byte, error) {
+	//------------------------------------------------------------
+	return decompress(flate.NewReader(bytes.NewReader(dataBytes)), maxSize...)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// CompressBase64 => gzip compresses then Base64 encodes
+//------------------------------------------------------------
+
+func CompressBase64(dataString string) (string, error) {
+	//------------------------------------------------------------
+	compressedBytes, err := Gzip_compress([]byte(dataString))
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(AppendBase64(nil, compressedBytes)), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// DecompressBase64 => Base64 decodes then gzip decompresses
+//------------------------------------------------------------
+
+func DecompressBase64(dataString string, maxSize ...int64) (string, error) {
+	//------------------------------------------------------------
+	compressedBytes, err := AppendBase64_decode(nil, []byte(dataString))
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	dataBytes, err := Gzip_decompress(compressedBytes, maxSize...)
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(dataBytes), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CompressBase64url => gzip compresses then Base64url encodes
+//------------------------------------------------------------
+
+func CompressBase64url(dataString string) (string, error) {
+	//------------------------------------------------------------
+	compressedBytes, err := Gzip_compress([]byte(dataString))
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(AppendBase64url(nil, compressedBytes)), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// DecompressBase64url => Base64url decodes then gzip decompresses
+//------------------------------------------------------------
+
+func DecompressBase64url(dataString string, maxSize ...int64) (string, error) {
+	//------------------------------------------------------------
+	compressedBytes, err := AppendBase64url_decode(nil, []byte(dataString))
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	dataBytes, err := Gzip_decompress(compressedBytes, maxSize...)
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(dataBytes), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CompressBase91 => gzip compresses then Base91 encodes
+//------------------------------------------------------------
+
+func CompressBase91(dataString string, escapeBool bool) (string, error) {
+	//------------------------------------------------------------
+	compressedBytes, err := Gzip_compress([]byte(dataString))
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(AppendBase91(nil, compressedBytes, escapeBool)), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// DecompressBase91 => Base91 decodes then gzip decompresses
+//------------------------------------------------------------
+
+func DecompressBase91(dataString string, unescapeBool bool, maxSize ...int64) (string, error) {
+	//------------------------------------------------------------
+	compressedBytes, err := AppendBase91_decode(nil, []byte(dataString), unescapeBool)
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	dataBytes, err := Gzip_decompress(compressedBytes, maxSize...)
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(dataBytes), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// compress
+//------------------------------------------------------------
+
+func compress(compressBuffer *bytes.Buffer, writer io.WriteCloser, dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	if _, err := writer.Write(dataBytes); err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	return compressBuffer.Bytes(), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// decompress => reads at most maxSize bytes from reader
+//------------------------------------------------------------
+
+func decompress(reader io.ReadCloser, maxSize ...int64) ([]byte, error) {
+	//------------------------------------------------------------
+	defer reader.Close()
+	//------------------------------------------------------------
+	maxBytes := DECOMPRESS_MAX_SIZE
+	//--------------------
+	if len(maxSize) > 0 && maxSize[0] > 0 {
+		maxBytes = maxSize[0]
+	}
+	//------------------------------------------------------------
+	// read one extra byte so that data exactly maxBytes long is allowed
+	dataBytes, err := io.ReadAll(io.LimitReader(reader, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	if int64(len(dataBytes)) > maxBytes {
+		return nil, fmt.Errorf("%w (%d bytes)", ErrDecompressMaxSize, maxBytes)
+	}
+	//------------------------------------------------------------
+	return dataBytes, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,127 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Gzip / Zlib / Deflate
+//------------------------------------------------------------
+
+func TestCompress(t *testing.T) {
+	//------------------------------------------------------------
+	dataString := strings.Repeat("ABC <> &quot; £ 日本語\U0001f427 ", 100)
+	//------------------------------------------------------------
+	testCases := []struct {
+		name           string
+		compressFunc   func([]byte) ([]byte, error)
+		decompressFunc func([]byte, ...int64) ([]byte, error)
+	}{
+		{"gzip", Gzip_compress, Gzip_decompress},
+		{"zlib", Zlib_compress, Zlib_decompress},
+		{"deflate", Deflate_compress, Deflate_decompress},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		compressedBytes, err := testCase.compressFunc([]byte(dataString))
+		//--------------------
+		if err != nil {
+			t.Errorf("(%s) %v", testCase.name, err)
+			continue
+		}
+		//--------------------
+		if len(compressedBytes) >= len(dataString) {
+			t.Errorf("(%s) compressed length = %d but should be less than %d", testCase.name, len(compressedBytes), len(dataString))
+		}
+		//--------------------
+		resultBytes, err := testCase.decompressFunc(compressedBytes)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%s) %v", testCase.name, err)
+		} else if string(resultBytes) != dataString {
+			t.Errorf("(%s) resultString = %q but should = %q", testCase.name, string(resultBytes), dataString)
+		}
+		//--------------------
+		// exactly the maximum size is allowed
+		if _, err = testCase.decompressFunc(compressedBytes, int64(len(dataString))); err != nil {
+			t.Errorf("(%s) %v", testCase.name, err)
+		}
+		//--------------------
+		if _, err = testCase.decompressFunc(compressedBytes, int64(len(dataString)-1)); !errors.Is(err, ErrDecompressMaxSize) {
+			t.Errorf("(%s) err = %v but should = %v", testCase.name, err, ErrDecompressMaxSize)
+		}
+		//--------------------
+		if _, err = testCase.decompressFunc([]byte("not compressed data")); err == nil {
+			t.Errorf("(%s) invalid data should return an error", testCase.name)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CompressBase64 / CompressBase64url / CompressBase91
+//------------------------------------------------------------
+
+func TestCompressEncode(t *testing.T) {
+	//------------------------------------------------------------
+	dataString := strings.Repeat("ABC <> &quot; £ 日本語\U0001f427 ", 100)
+	//------------------------------------------------------------
+	encodedString, err := CompressBase64(dataString)
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	if resultString, err := DecompressBase64(encodedString); err != nil {
+		t.Error(err)
+	} else if resultString != dataString {
+		t.Errorf("resultString = %q but should = %q", resultString, dataString)
+	}
+	//------------------------------------------------------------
+	encodedString, err = CompressBase64url(dataString)
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	if resultString, err := DecompressBase64url(encodedString); err != nil {
+		t.Error(err)
+	} else if resultString != dataString {
+		t.Errorf("resultString = %q but should = %q", resultString, dataString)
+	}
+	//------------------------------------------------------------
+	encodedString, err = CompressBase91(dataString, true)
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	if strings.ContainsAny(encodedString, "\"$`") {
+		t.Errorf("encodedString = %q should not contain escaped characters", encodedString)
+	}
+	//--------------------
+	if resultString, err := DecompressBase91(encodedString, true); err != nil {
+		t.Error(err)
+	} else if resultString != dataString {
+		t.Errorf("resultString = %q but should = %q", resultString, dataString)
+	}
+	//--------------------
+	if _, err := DecompressBase91(encodedString, true, 10); !errors.Is(err, ErrDecompressMaxSize) {
+		t.Errorf("err = %v but should = %v", err, ErrDecompressMaxSize)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
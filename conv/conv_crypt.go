@@ -0,0 +1,194 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+//------------------------------------------------------------
+// envelope (Base64url encoded):
+// version (1 byte) | salt (16 bytes) | nonce (12 bytes) | AES-256-GCM ciphertext + tag
+//------------------------------------------------------------
+
+const CRYPT_VERSION byte = 1
+
+//------------------------------------------------------------
+
+const (
+	cryptSaltSize  = 16
+	cryptKeySize   = 32
+	cryptNonceSize = 12 // standard GCM nonce
+	cryptTagSize   = 16 // GCM authentication tag
+	// scrypt parameters used by version 1 envelopes
+	cryptScryptN = 1 << 15
+	cryptScryptR = 8
+	cryptScryptP = 1
+)
+
+//------------------------------------------------------------
+
+var (
+	ErrCryptPassphrase = errors.New("passphrase must not be empty")
+	ErrCryptTruncated  = errors.New("encrypted data truncated")
+	ErrCryptVersion    = errors.New("unsupported encrypted data version")
+	ErrCryptAuth       = errors.New("decryption failed (wrong passphrase or data modified)")
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Encrypt => AES-256-GCM with a scrypt derived key, returned as a Base64url envelope
+//------------------------------------------------------------
+
+func Encrypt(dataString string, passphrase string) (string, error) {
+	//------------------------------------------------------------
+	envelopeBytes, err := Encrypt_bytes([]byte(dataString), passphrase)
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(AppendBase64url(nil, envelopeBytes)), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Decrypt => decrypts a Base64url envelope created by Encrypt
+//------------------------------------------------------------
+
+func Decrypt(dataString string, passphrase string) (string, error) {
+	//------------------------------------------------------------
+	envelopeBytes, err := AppendBase64url_decode(nil, []byte(dataString))
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	dataBytes, err := Decrypt_bytes(envelopeBytes, passphrase)
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return string(dataBytes), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Encrypt_bytes => returns the raw (unencoded) envelope
+//------------------------------------------------------------
+
+func Encrypt_bytes(dataBytes []byte, passphrase string) ([]byte, error) {
+	//------------------------------------------------------------
+	if passphrase == "" {
+		return nil, ErrCryptPassphrase
+	}
+	//------------------------------------------------------------
+	headerBytes := make([]byte, 1+cryptSaltSize)
+	headerBytes[0] = CRYPT_VERSION
+	//--------------------
+	if _, err := rand.Read(headerBytes[1:]); err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	aead, err := cryptAEAD(passphrase, headerBytes[1:])
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	nonceBytes := make([]byte, aead.NonceSize())
+	//--------------------
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	envelopeBytes := make([]byte, 0, len(headerBytes)+len(nonceBytes)+len(dataBytes)+aead.Overhead())
+	envelopeBytes = append(envelopeBytes, headerBytes...)
+	envelopeBytes = append(envelopeBytes, nonceBytes...)
+	//------------------------------------------------------------
+	// the header is authenticated so the version and salt cannot be altered
+	return aead.Seal(envelopeBytes, nonceBytes, dataBytes, headerBytes), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Decrypt_bytes => decrypts a raw envelope created by Encrypt_bytes
+//------------------------------------------------------------
+
+func Decrypt_bytes(envelopeBytes []byte, passphrase string) ([]byte, error) {
+	//------------------------------------------------------------
+	if passphrase == "" {
+		return nil, ErrCryptPassphrase
+	}
+	//------------------------------------------------------------
+	if len(envelopeBytes) == 0 {
+		return nil, ErrCryptTruncated
+	}
+	//--------------------
+	if envelopeBytes[0] != CRYPT_VERSION {
+		return nil, ErrCryptVersion
+	}
+	//------------------------------------------------------------
+	// checked before the (deliberately slow) key derivation
+	if len(envelopeBytes) < 1+cryptSaltSize+cryptNonceSize+cryptTagSize {
+		return nil, ErrCryptTruncated
+	}
+	//------------------------------------------------------------
+	headerBytes := envelopeBytes[:1+cryptSaltSize]
+	//------------------------------------------------------------
+	aead, err := cryptAEAD(passphrase, headerBytes[1:])
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	nonceBytes := envelopeBytes[len(headerBytes) : len(headerBytes)+cryptNonceSize]
+	cipherBytes := envelopeBytes[len(headerBytes)+cryptNonceSize:]
+	//------------------------------------------------------------
+	dataBytes, err := aead.Open(nil, nonceBytes, cipherBytes, headerBytes)
+	if err != nil {
+		return nil, ErrCryptAuth
+	}
+	//------------------------------------------------------------
+	return dataBytes, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// cryptAEAD => derives the key from passphrase and salt
+//------------------------------------------------------------
+
+func cryptAEAD(passphrase string, saltBytes []byte) (cipher.AEAD, error) {
+	//------------------------------------------------------------
+	keyBytes, err := scrypt.Key([]byte(passphrase), saltBytes, cryptScryptN, cryptScryptR, cryptScryptP, cryptKeySize)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	block, err := aes.NewCipher(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	return cipher.NewGCM(block)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
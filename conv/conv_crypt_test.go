@@ -0,0 +1,113 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"errors"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Encrypt / Decrypt
+//------------------------------------------------------------
+
+func TestEncrypt(t *testing.T) {
+	//------------------------------------------------------------
+	dataString := "ABC <> &quot; £ 日本語 🐧"
+	passphrase := "correct horse battery staple"
+	//------------------------------------------------------------
+	encryptedString, err := Encrypt(dataString, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	encryptedString2, err := Encrypt(dataString, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	if encryptedString == encryptedString2 {
+		t.Errorf("encrypting twice should produce different output (random salt and nonce)")
+	}
+	//------------------------------------------------------------
+	for _, testString := range []string{encryptedString, encryptedString2} {
+		//--------------------
+		resultString, err := Decrypt(testString, passphrase)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if resultString != dataString {
+			t.Errorf("resultString = %q but should = %q", resultString, dataString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if _, err = Decrypt(encryptedString, "wrong passphrase"); !errors.Is(err, ErrCryptAuth) {
+		t.Errorf("err = %v but should = %v", err, ErrCryptAuth)
+	}
+	//------------------------------------------------------------
+	if _, err = Encrypt(dataString, ""); !errors.Is(err, ErrCryptPassphrase) {
+		t.Errorf("err = %v but should = %v", err, ErrCryptPassphrase)
+	}
+	//------------------------------------------------------------
+	if _, err = Decrypt("!!!", passphrase); err == nil {
+		t.Errorf("invalid Base64url should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Decrypt_bytes
+//------------------------------------------------------------
+
+func TestDecrypt_bytes(t *testing.T) {
+	//------------------------------------------------------------
+	passphrase := "passphrase"
+	//------------------------------------------------------------
+	envelopeBytes, err := Encrypt_bytes([]byte("secret"), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	tamperedBytes := append([]byte{}, envelopeBytes...)
+	tamperedBytes[len(tamperedBytes)-1] ^= 1
+	//--------------------
+	saltBytes := append([]byte{}, envelopeBytes...)
+	saltBytes[1] ^= 1
+	//--------------------
+	versionBytes := append([]byte{}, envelopeBytes...)
+	versionBytes[0] = CRYPT_VERSION + 1
+	//------------------------------------------------------------
+	testCases := []struct {
+		name        string
+		dataBytes   []byte
+		expectedErr error
+	}{
+		{"empty", nil, ErrCryptTruncated},
+		{"version", versionBytes, ErrCryptVersion},
+		{"header", envelopeBytes[:10], ErrCryptTruncated},
+		{"nonce", envelopeBytes[:20], ErrCryptTruncated},
+		{"tag", envelopeBytes[:1+16+12+15], ErrCryptTruncated},
+		{"ciphertext", tamperedBytes, ErrCryptAuth},
+		{"salt", saltBytes, ErrCryptAuth},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		_, err := Decrypt_bytes(testCase.dataBytes, passphrase)
+		//--------------------
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("(%s) err = %v but should = %v", testCase.name, err, testCase.expectedErr)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
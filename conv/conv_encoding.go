@@ -0,0 +1,507 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+//------------------------------------------------------------
+
+const Z85_CHARSET = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+//------------------------------------------------------------
+
+// DecodeError => returned when invalid data is found while decoding
+type DecodeError struct {
+	Encoding string
+	Offset   int64
+	Reason   string
+}
+
+func (err DecodeError) Error() string {
+	//------------------------------------------------------------
+	if err.Reason != "" {
+		return fmt.Sprintf("invalid %s data at input byte %d: %s", err.Encoding, err.Offset, err.Reason)
+	}
+	//------------------------------------------------------------
+	return fmt.Sprintf("invalid %s data at input byte %d", err.Encoding, err.Offset)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// init
+//------------------------------------------------------------
+
+func init() {
+	//------------------------------------------------------------
+	RegisterCodec("hex", CodecFuncs{
+		func(dataBytes []byte) []byte { return AppendHex([]byte{}, dataBytes) },
+		func(dataBytes []byte) ([]byte, error) { return AppendHex_decode([]byte{}, dataBytes) },
+	})
+	//--------------------
+	RegisterCodec("base32", CodecFuncs{
+		func(dataBytes []byte) []byte { return AppendBase32([]byte{}, dataBytes, true) },
+		func(dataBytes []byte) ([]byte, error) { return AppendBase32_decode([]byte{}, dataBytes) },
+	})
+	RegisterCodec("base32-nopad", CodecFuncs{
+		func(dataBytes []byte) []byte { return AppendBase32([]byte{}, dataBytes, false) },
+		func(dataBytes []byte) ([]byte, error) { return AppendBase32_decode([]byte{}, dataBytes) },
+	})
+	RegisterCodec("base32hex", CodecFuncs{
+		func(dataBytes []byte) []byte { return AppendBase32hex([]byte{}, dataBytes, true) },
+		func(dataBytes []byte) ([]byte, error) { return AppendBase32hex_decode([]byte{}, dataBytes) },
+	})
+	RegisterCodec("base32hex-nopad", CodecFuncs{
+		func(dataBytes []byte) []byte { return AppendBase32hex([]byte{}, dataBytes, false) },
+		func(dataBytes []byte) ([]byte, error) { return AppendBase32hex_decode([]byte{}, dataBytes) },
+	})
+	//--------------------
+	RegisterCodec("ascii85", CodecFuncs{
+		func(dataBytes []byte) []byte { return AppendAscii85([]byte{}, dataBytes, false) },
+		func(dataBytes []byte) ([]byte, error) { return AppendAscii85_decode([]byte{}, dataBytes) },
+	})
+	//------------------------------------------------------------
+	// z85 is not registered as it can only encode data in multiples of 4 bytes
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Hex_encode
+//------------------------------------------------------------
+
+func Hex_encode(dataString string) string {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString
+	}
+	//------------------------------------------------------------
+	return string(AppendHex(nil, []byte(dataString)))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Hex_decode
+//------------------------------------------------------------
+
+func Hex_decode(dataString string) (string, error) {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString, nil
+	}
+	//------------------------------------------------------------
+	dataBytes, err := AppendHex_decode(nil, []byte(dataString))
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendHex => appends the lower case hex encoding of src to dst
+//------------------------------------------------------------
+
+func AppendHex(dst []byte, src []byte) []byte {
+	//------------------------------------------------------------
+	return hex.AppendEncode(dst, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendHex_decode => appends the decoded hex data (upper or lower case) in src to dst
+//------------------------------------------------------------
+
+func AppendHex_decode(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	for index, value := range src {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", rune(value)) {
+			return dst, DecodeError{Encoding: "hex", Offset: int64(index)}
+		}
+	}
+	//--------------------
+	if len(src)%2 != 0 {
+		return dst, DecodeError{Encoding: "hex", Offset: int64(len(src) - 1), Reason: "odd length"}
+	}
+	//------------------------------------------------------------
+	return hex.AppendDecode(dst, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Base32_encode
+//------------------------------------------------------------
+
+func Base32_encode(dataString string, paddingBool bool) string {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString
+	}
+	//------------------------------------------------------------
+	return string(AppendBase32(nil, []byte(dataString), paddingBool))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base32_decode => accepts padded or unpadded data
+//------------------------------------------------------------
+
+func Base32_decode(dataString string) (string, error) {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString, nil
+	}
+	//------------------------------------------------------------
+	dataBytes, err := AppendBase32_decode(nil, []byte(dataString))
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base32hex_encode
+//------------------------------------------------------------
+
+func Base32hex_encode(dataString string, paddingBool bool) string {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString
+	}
+	//------------------------------------------------------------
+	return string(AppendBase32hex(nil, []byte(dataString), paddingBool))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base32hex_decode => accepts padded or unpadded data
+//------------------------------------------------------------
+
+func Base32hex_decode(dataString string) (string, error) {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString, nil
+	}
+	//------------------------------------------------------------
+	dataBytes, err := AppendBase32hex_decode(nil, []byte(dataString))
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase32 => appends the Base32 encoding of src to dst
+//------------------------------------------------------------
+
+func AppendBase32(dst []byte, src []byte, paddingBool bool) []byte {
+	//------------------------------------------------------------
+	return base32Encoding(base32.StdEncoding, paddingBool).AppendEncode(dst, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase32_decode => appends the decoded Base32 data in src to dst
+//------------------------------------------------------------
+
+func AppendBase32_decode(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	return base32AppendDecode(dst, src, base32.StdEncoding, "base32")
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase32hex => appends the Base32 (extended hex alphabet) encoding of src to dst
+//------------------------------------------------------------
+
+func AppendBase32hex(dst []byte, src []byte, paddingBool bool) []byte {
+	//------------------------------------------------------------
+	return base32Encoding(base32.HexEncoding, paddingBool).AppendEncode(dst, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase32hex_decode => appends the decoded Base32 (extended hex alphabet) data in src to dst
+//------------------------------------------------------------
+
+func AppendBase32hex_decode(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	return base32AppendDecode(dst, src, base32.HexEncoding, "base32hex")
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// base32Encoding
+//------------------------------------------------------------
+
+func base32Encoding(encoding *base32.Encoding, paddingBool bool) *base32.Encoding {
+	//------------------------------------------------------------
+	if paddingBool {
+		return encoding
+	}
+	//------------------------------------------------------------
+	return encoding.WithPadding(base32.NoPadding)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// base32AppendDecode
+//------------------------------------------------------------
+
+func base32AppendDecode(dst []byte, src []byte, encoding *base32.Encoding, encodingName string) ([]byte, error) {
+	//------------------------------------------------------------
+	if len(src) == 0 {
+		return dst, nil
+	}
+	//------------------------------------------------------------
+	dstLength := len(dst)
+	//------------------------------------------------------------
+	dst, err := base32Encoding(encoding, slices.Contains(src, '=')).AppendDecode(dst, src)
+	//------------------------------------------------------------
+	if err != nil {
+		//--------------------
+		var corruptInputError base32.CorruptInputError
+		if errors.As(err, &corruptInputError) {
+			err = DecodeError{Encoding: encodingName, Offset: int64(corruptInputError)}
+		}
+		//--------------------
+		return dst[:dstLength], err
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return dst, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Ascii85_encode
+//------------------------------------------------------------
+// adobeBool => wraps the output in the Adobe <~ ~> delimiters
+//------------------------------------------------------------
+
+func Ascii85_encode(dataString string, adobeBool bool) string {
+	//------------------------------------------------------------
+	if dataString == "" && !adobeBool {
+		return dataString
+	}
+	//------------------------------------------------------------
+	return string(AppendAscii85(nil, []byte(dataString), adobeBool))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Ascii85_decode => accepts data with or without the Adobe <~ ~> delimiters
+//------------------------------------------------------------
+
+func Ascii85_decode(dataString string) (string, error) {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString, nil
+	}
+	//------------------------------------------------------------
+	dataBytes, err := AppendAscii85_decode(nil, []byte(dataString))
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendAscii85 => appends the Ascii85 (btoa) encoding of src to dst
+//------------------------------------------------------------
+
+func AppendAscii85(dst []byte, src []byte, adobeBool bool) []byte {
+	//------------------------------------------------------------
+	if adobeBool {
+		dst = append(dst, '<', '~')
+	}
+	//------------------------------------------------------------
+	dstLength := len(dst)
+	dst = slices.Grow(dst, ascii85.MaxEncodedLen(len(src)))
+	//--------------------
+	dst = dst[:dstLength+ascii85.Encode(dst[dstLength:cap(dst)], src)]
+	//------------------------------------------------------------
+	if adobeBool {
+		dst = append(dst, '~', '>')
+	}
+	//------------------------------------------------------------
+	return dst
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendAscii85_decode => appends the decoded Ascii85 data in src to dst
+//------------------------------------------------------------
+
+func AppendAscii85_decode(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	// strip optional Adobe delimiters (whitespace is ignored by the decoder)
+	trimmedBytes := bytes.TrimLeft(src, " \t\r\n\f\v")
+	//--------------------
+	offset := len(src) - len(trimmedBytes)
+	//--------------------
+	trimmedBytes = bytes.TrimSpace(trimmedBytes)
+	//--------------------
+	if bytes.HasPrefix(trimmedBytes, []byte("<~")) {
+		trimmedBytes = trimmedBytes[2:]
+		offset += 2
+	}
+	//--------------------
+	trimmedBytes = bytes.TrimSuffix(trimmedBytes, []byte("~>"))
+	//------------------------------------------------------------
+	if len(trimmedBytes) == 0 {
+		return dst, nil
+	}
+	//------------------------------------------------------------
+	// each 'z' can expand to 4 bytes
+	dstLength := len(dst)
+	dst = slices.Grow(dst, len(trimmedBytes)*4)
+	//------------------------------------------------------------
+	outputLength, _, err := ascii85.Decode(dst[dstLength:cap(dst)], trimmedBytes, true)
+	//------------------------------------------------------------
+	if err != nil {
+		//--------------------
+		var corruptInputError ascii85.CorruptInputError
+		if errors.As(err, &corruptInputError) {
+			err = DecodeError{Encoding: "ascii85", Offset: int64(corruptInputError) + int64(offset)}
+		}
+		//--------------------
+		return dst[:dstLength], err
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return dst[:dstLength+outputLength], nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Z85_encode => ZeroMQ Z85 (data length must be a multiple of 4)
+//------------------------------------------------------------
+
+func Z85_encode(dataString string) (string, error) {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString, nil
+	}
+	//------------------------------------------------------------
+	dataBytes, err := AppendZ85(nil, []byte(dataString))
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Z85_decode => ZeroMQ Z85 (data length must be a multiple of 5)
+//------------------------------------------------------------
+
+func Z85_decode(dataString string) (string, error) {
+	//------------------------------------------------------------
+	if dataString == "" {
+		return dataString, nil
+	}
+	//------------------------------------------------------------
+	dataBytes, err := AppendZ85_decode(nil, []byte(dataString))
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendZ85 => appends the Z85 encoding of src to dst
+//------------------------------------------------------------
+
+func AppendZ85(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	if len(src)%4 != 0 {
+		return dst, errors.New("z85 data length must be a multiple of 4")
+	}
+	//------------------------------------------------------------
+	for dataIndex := 0; dataIndex < len(src); dataIndex += 4 {
+		//--------------------
+		value := uint32(src[dataIndex])<<24 | uint32(src[dataIndex+1])<<16 | uint32(src[dataIndex+2])<<8 | uint32(src[dataIndex+3])
+		//--------------------
+		dst = append(dst,
+			Z85_CHARSET[value/52200625%85],
+			Z85_CHARSET[value/614125%85],
+			Z85_CHARSET[value/7225%85],
+			Z85_CHARSET[value/85%85],
+			Z85_CHARSET[value%85],
+		)
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return dst, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendZ85_decode => appends the decoded Z85 data in src to dst
+//------------------------------------------------------------
+
+func AppendZ85_decode(dst []byte, src []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	if len(src)%5 != 0 {
+		return dst, DecodeError{Encoding: "z85", Offset: int64(len(src) - len(src)%5), Reason: "length must be a multiple of 5"}
+	}
+	//------------------------------------------------------------
+	dstLength := len(dst)
+	//------------------------------------------------------------
+	for dataIndex := 0; dataIndex < len(src); dataIndex += 5 {
+		//--------------------
+		var value uint64
+		//--------------------
+		for subIndex := dataIndex; subIndex < dataIndex+5; subIndex++ {
+			//--------------------
+			charIndex := strings.IndexByte(Z85_CHARSET, src[subIndex])
+			if charIndex == -1 {
+				return dst[:dstLength], DecodeError{Encoding: "z85", Offset: int64(subIndex)}
+			}
+			//--------------------
+			value = value*85 + uint64(charIndex)
+			//--------------------
+		}
+		//--------------------
+		if value > 0xFFFFFFFF {
+			return dst[:dstLength], DecodeError{Encoding: "z85", Offset: int64(dataIndex), Reason: "group value overflow"}
+		}
+		//--------------------
+		dst = append(dst, byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return dst, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
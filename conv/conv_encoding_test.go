@@ -0,0 +1,242 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"errors"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Hex_encode / Hex_decode
+//------------------------------------------------------------
+
+func TestHex(t *testing.T) {
+	//--------------------------------------------------
+	dataString := "ABC \U0001f427"
+	hexString := "41424320f09f90a7"
+	//--------------------------------------------------
+	resultString := Hex_encode(dataString)
+	//--------------------
+	if resultString != hexString {
+		t.Errorf("resultString = %q but should = %q", resultString, hexString)
+	}
+	//--------------------------------------------------
+	for _, encodedString := range []string{hexString, "41424320F09F90A7"} {
+		//--------------------
+		resultString, err := Hex_decode(encodedString)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if resultString != dataString {
+			t.Errorf("resultString = %q but should = %q", resultString, dataString)
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+	testCases := []struct {
+		dataString     string
+		expectedOffset int64
+	}{
+		{"4142x3", 4},
+		{"41424", 4},
+	}
+	//--------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		var decodeError DecodeError
+		//--------------------
+		_, err := Hex_decode(testCase.dataString)
+		//--------------------
+		if !errors.As(err, &decodeError) {
+			t.Errorf("(%q) err = %v but should be a DecodeError", testCase.dataString, err)
+		} else if decodeError.Offset != testCase.expectedOffset {
+			t.Errorf("(%q) offset = %d but should = %d", testCase.dataString, decodeError.Offset, testCase.expectedOffset)
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base32_encode / Base32_decode
+//------------------------------------------------------------
+
+func TestBase32(t *testing.T) {
+	//--------------------------------------------------
+	testCases := []struct {
+		dataString     string
+		paddingBool    bool
+		expectedString string
+	}{
+		{"", true, ""},
+		{"foobar", true, "MZXW6YTBOI======"},
+		{"foobar", false, "MZXW6YTBOI"},
+		{"fooba", true, "MZXW6YTB"},
+	}
+	//--------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		resultString := Base32_encode(testCase.dataString, testCase.paddingBool)
+		//--------------------
+		if resultString != testCase.expectedString {
+			t.Errorf("(%q, %v) resultString = %q but should = %q", testCase.dataString, testCase.paddingBool, resultString, testCase.expectedString)
+		}
+		//--------------------
+		resultString, err := Base32_decode(testCase.expectedString)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if resultString != testCase.dataString {
+			t.Errorf("(%q) resultString = %q but should = %q", testCase.expectedString, resultString, testCase.dataString)
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+	var decodeError DecodeError
+	//--------------------
+	_, err := Base32_decode("MZXW6Y1BOI")
+	//--------------------
+	if !errors.As(err, &decodeError) || decodeError.Offset != 6 {
+		t.Errorf("err = %v but should be a DecodeError at offset 6", err)
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Base32hex_encode / Base32hex_decode
+//------------------------------------------------------------
+
+func TestBase32hex(t *testing.T) {
+	//--------------------------------------------------
+	resultString := Base32hex_encode("foobar", true)
+	//--------------------
+	if resultString != "CPNMUOJ1E8======" {
+		t.Errorf("resultString = %q but should = %q", resultString, "CPNMUOJ1E8======")
+	}
+	//--------------------------------------------------
+	resultString = Base32hex_encode("foobar", false)
+	//--------------------
+	if resultString != "CPNMUOJ1E8" {
+		t.Errorf("resultString = %q but should = %q", resultString, "CPNMUOJ1E8")
+	}
+	//--------------------------------------------------
+	for _, encodedString := range []string{"CPNMUOJ1E8======", "CPNMUOJ1E8"} {
+		//--------------------
+		resultString, err := Base32hex_decode(encodedString)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if resultString != "foobar" {
+			t.Errorf("resultString = %q but should = %q", resultString, "foobar")
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Ascii85_encode / Ascii85_decode
+//------------------------------------------------------------
+
+func TestAscii85(t *testing.T) {
+	//--------------------------------------------------
+	testCases := []struct {
+		dataString     string
+		adobeBool      bool
+		expectedString string
+	}{
+		{"Hello, World!", false, "87cURD_*#4DfTZ)+T"},
+		{"Hello, World!", true, "<~87cURD_*#4DfTZ)+T~>"},
+		{"\x00\x00\x00\x00abc", false, "z@:E^"},
+	}
+	//--------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		resultString := Ascii85_encode(testCase.dataString, testCase.adobeBool)
+		//--------------------
+		if resultString != testCase.expectedString {
+			t.Errorf("(%q, %v) resultString = %q but should = %q", testCase.dataString, testCase.adobeBool, resultString, testCase.expectedString)
+		}
+		//--------------------
+		resultString, err := Ascii85_decode(" " + testCase.expectedString + "\n")
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if resultString != testCase.dataString {
+			t.Errorf("(%q) resultString = %q but should = %q", testCase.expectedString, resultString, testCase.dataString)
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+	var decodeError DecodeError
+	//--------------------
+	_, err := Ascii85_decode(" <~87cU\x7fRD_*~>")
+	//--------------------
+	if !errors.As(err, &decodeError) || decodeError.Offset != 7 {
+		t.Errorf("err = %v but should be a DecodeError at offset 7", err)
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Z85_encode / Z85_decode
+//------------------------------------------------------------
+
+func TestZ85(t *testing.T) {
+	//--------------------------------------------------
+	dataString := "\x86\x4F\xD2\x6F\xB5\x59\xF7\x5B"
+	z85String := "HelloWorld"
+	//--------------------------------------------------
+	resultString, err := Z85_encode(dataString)
+	//--------------------
+	if err != nil {
+		t.Error(err)
+	} else if resultString != z85String {
+		t.Errorf("resultString = %q but should = %q", resultString, z85String)
+	}
+	//--------------------------------------------------
+	resultString, err = Z85_decode(z85String)
+	//--------------------
+	if err != nil {
+		t.Error(err)
+	} else if resultString != dataString {
+		t.Errorf("resultString = %q but should = %q", resultString, dataString)
+	}
+	//--------------------------------------------------
+	if _, err = Z85_encode("ABC"); err == nil {
+		t.Error("data length that is not a multiple of 4 should return an error")
+	}
+	//--------------------------------------------------
+	testCases := []struct {
+		dataString     string
+		expectedOffset int64
+	}{
+		{"Hello~orld", 5},
+		{"HelloWor", 5},
+		{"%%%%%", 0},
+	}
+	//--------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		var decodeError DecodeError
+		//--------------------
+		_, err := Z85_decode(testCase.dataString)
+		//--------------------
+		if !errors.As(err, &decodeError) {
+			t.Errorf("(%q) err = %v but should be a DecodeError", testCase.dataString, err)
+		} else if decodeError.Offset != testCase.expectedOffset {
+			t.Errorf("(%q) offset = %d but should = %d", testCase.dataString, decodeError.Offset, testCase.expectedOffset)
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
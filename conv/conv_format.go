@@ -0,0 +1,153 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------
+// payload formats for map[string]any / []any trees
+//------------------------------------------------------------
+
+const FORMAT_JSON = "json"
+const FORMAT_CBOR = "cbor"
+const FORMAT_MSGPACK = "msgpack"
+
+//------------------------------------------------------------
+
+// nesting limit when decoding binary formats
+const maxTreeDepth = 1000
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// MarshalFormat => encodes input as "json" (default when blank), "cbor" or "msgpack"
+//------------------------------------------------------------
+
+func MarshalFormat(format string, input interface{}) ([]byte, error) {
+	//------------------------------------------------------------
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FORMAT_JSON:
+		return JSON_Marshal(input)
+	case FORMAT_CBOR:
+		return CBOR_Marshal(input)
+	case FORMAT_MSGPACK:
+		return MsgPack_Marshal(input)
+	}
+	//------------------------------------------------------------
+	return nil, fmt.Errorf("unknown format: %s", format)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UnmarshalFormat => decodes "json" (default when blank), "cbor" or "msgpack" data
+//------------------------------------------------------------
+
+func UnmarshalFormat(format string, data []byte) (interface{}, error) {
+	//------------------------------------------------------------
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FORMAT_JSON:
+		return JSON_decode(string(data))
+	case FORMAT_CBOR:
+		return CBOR_Unmarshal(data)
+	case FORMAT_MSGPACK:
+		return MsgPack_Unmarshal(data)
+	}
+	//------------------------------------------------------------
+	return nil, fmt.Errorf("unknown format: %s", format)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// treeValue => reduces values not handled directly by the binary
+// encoders (structs, typed slices and maps etc.) to a plain tree via json
+//------------------------------------------------------------
+
+func treeValue(input any) (any, error) {
+	//------------------------------------------------------------
+	jsonBytes, err := JSON_Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	return jsonDecode[any](string(jsonBytes), false, true)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// treeNumber => converts a json.Number to int64, uint64 or float64
+//------------------------------------------------------------
+
+func treeNumber(number json.Number) (any, error) {
+	//------------------------------------------------------------
+	if intValue, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+		return intValue, nil
+	}
+	//--------------------
+	if uintValue, err := strconv.ParseUint(string(number), 10, 64); err == nil {
+		return uintValue, nil
+	}
+	//------------------------------------------------------------
+	floatValue, err := strconv.ParseFloat(string(number), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s", number)
+	}
+	//------------------------------------------------------------
+	return floatValue, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// floatAsInt => whole numbers are written as integers as they are more compact
+//------------------------------------------------------------
+
+func floatAsInt(value float64) (int64, bool) {
+	//------------------------------------------------------------
+	if value != math.Trunc(value) || math.Abs(value) > 1<<53 || math.Signbit(value) && value == 0 {
+		return 0, false
+	}
+	//------------------------------------------------------------
+	return int64(value), true
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// sortedKeys
+//------------------------------------------------------------
+
+func sortedKeys(valueMap map[string]any) []string {
+	//------------------------------------------------------------
+	keys := make([]string, 0, len(valueMap))
+	//--------------------
+	for key := range valueMap {
+		keys = append(keys, key)
+	}
+	//--------------------
+	slices.Sort(keys)
+	//------------------------------------------------------------
+	return keys
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,57 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// MarshalFormat / UnmarshalFormat
+//------------------------------------------------------------
+
+func TestMarshalFormat(t *testing.T) {
+	//------------------------------------------------------------
+	jsonString := `{"a":[1,"<b>",null],"c":{"d":1.5}}`
+	//------------------------------------------------------------
+	input := mustJSON_decode(t, jsonString)
+	//------------------------------------------------------------
+	for _, format := range []string{"", "json", "CBOR", "msgpack"} {
+		//--------------------
+		dataBytes, err := MarshalFormat(format, input)
+		if err != nil {
+			t.Errorf("(%q) %v", format, err)
+			continue
+		}
+		//--------------------
+		value, err := UnmarshalFormat(format, dataBytes)
+		if err != nil {
+			t.Errorf("(%q) %v", format, err)
+			continue
+		}
+		//--------------------
+		resultString, _ := JSON_encode(value)
+		//--------------------
+		if resultString != jsonString {
+			t.Errorf("(%q) resultString = %q but should = %q", format, resultString, jsonString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if _, err := MarshalFormat("xml", input); err == nil {
+		t.Error("unknown format should return an error")
+	}
+	//--------------------
+	if _, err := UnmarshalFormat("xml", nil); err == nil {
+		t.Error("unknown format should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,211 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+//------------------------------------------------------------
+// frame layout (before encoding):
+//
+//	version (1 byte) | payload length (uvarint) | payload | CRC32 IEEE (4 bytes big endian)
+//
+// the CRC32 covers everything before it
+//------------------------------------------------------------
+
+const FRAME_VERSION byte = 1
+
+//------------------------------------------------------------
+
+var ErrFrameTruncated = errors.New("frame truncated")
+var ErrFrameChecksum = errors.New("frame checksum mismatch")
+var ErrFrameVersion = errors.New("unsupported frame version")
+var ErrFrameTrailing = errors.New("unexpected data after frame")
+
+//------------------------------------------------------------
+
+// FrameError => wraps ErrFrameTruncated, ErrFrameChecksum, ErrFrameVersion or ErrFrameTrailing
+type FrameError struct {
+	Err      error
+	Expected uint32
+	Actual   uint32
+}
+
+func (err FrameError) Error() string {
+	//------------------------------------------------------------
+	switch err.Err {
+	case ErrFrameChecksum:
+		return fmt.Sprintf("%v (expected %08x but got %08x)", err.Err, err.Expected, err.Actual)
+	case ErrFrameTruncated, ErrFrameTrailing:
+		return fmt.Sprintf("%v (expected %d bytes but got %d)", err.Err, err.Expected, err.Actual)
+	case ErrFrameVersion:
+		return fmt.Sprintf("%v (%d)", err.Err, err.Actual)
+	}
+	//------------------------------------------------------------
+	return fmt.Sprint(err.Err)
+	//------------------------------------------------------------
+}
+
+func (err FrameError) Unwrap() error {
+	return err.Err
+}
+
+//------------------------------------------------------------
+
+type frameCodec struct {
+	codec Codec
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Frame_encode => frames data then encodes it using a registered codec (e.g. "base91")
+//------------------------------------------------------------
+
+func Frame_encode(dataString string, encoding string) (string, error) {
+	//------------------------------------------------------------
+	codec, exists := LookupCodec(encoding)
+	if !exists {
+		return "", fmt.Errorf("unknown encoding: %s", encoding)
+	}
+	//------------------------------------------------------------
+	return string(NewFrameCodec(codec).Encode([]byte(dataString))), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Frame_decode => decodes data using a registered codec then verifies the frame
+//------------------------------------------------------------
+
+func Frame_decode(dataString string, encoding string) (string, error) {
+	//------------------------------------------------------------
+	codec, exists := LookupCodec(encoding)
+	if !exists {
+		return "", fmt.Errorf("unknown encoding: %s", encoding)
+	}
+	//------------------------------------------------------------
+	// pasted data often picks up surrounding whitespace
+	dataBytes, err := NewFrameCodec(codec).Decode([]byte(strings.TrimSpace(dataString)))
+	//------------------------------------------------------------
+	return string(dataBytes), err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NewFrameCodec => returns a codec that frames data before encoding it with codec
+//------------------------------------------------------------
+
+func NewFrameCodec(codec Codec) Codec {
+	//------------------------------------------------------------
+	return frameCodec{codec: codec}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// frameCodec - Encode
+//------------------------------------------------------------
+
+func (frame frameCodec) Encode(dataBytes []byte) []byte {
+	//------------------------------------------------------------
+	return frame.codec.Encode(appendFrame(nil, dataBytes))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// frameCodec - Decode
+//------------------------------------------------------------
+
+func (frame frameCodec) Decode(dataBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	frameBytes, err := frame.codec.Decode(dataBytes)
+	if err != nil {
+		return []byte{}, err
+	}
+	//------------------------------------------------------------
+	return parseFrame(frameBytes)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// appendFrame
+//------------------------------------------------------------
+
+func appendFrame(dst []byte, payload []byte) []byte {
+	//------------------------------------------------------------
+	frameStart := len(dst)
+	//------------------------------------------------------------
+	dst = append(dst, FRAME_VERSION)
+	dst = binary.AppendUvarint(dst, uint64(len(payload)))
+	dst = append(dst, payload...)
+	//------------------------------------------------------------
+	return binary.BigEndian.AppendUint32(dst, crc32.ChecksumIEEE(dst[frameStart:]))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// parseFrame => returns the payload once the frame has been verified
+//------------------------------------------------------------
+
+func parseFrame(frameBytes []byte) ([]byte, error) {
+	//------------------------------------------------------------
+	if len(frameBytes) == 0 {
+		return []byte{}, FrameError{Err: ErrFrameTruncated, Expected: 1 + 1 + 4}
+	}
+	//------------------------------------------------------------
+	if frameBytes[0] != FRAME_VERSION {
+		return []byte{}, FrameError{Err: ErrFrameVersion, Actual: uint32(frameBytes[0])}
+	}
+	//------------------------------------------------------------
+	payloadLength, lengthSize := binary.Uvarint(frameBytes[1:])
+	if lengthSize <= 0 {
+		return []byte{}, FrameError{Err: ErrFrameTruncated, Expected: 1 + 1 + 4, Actual: uint32(len(frameBytes))}
+	}
+	//------------------------------------------------------------
+	headerLength := 1 + lengthSize
+	//--------------------
+	if payloadLength > uint64(len(frameBytes)) {
+		return []byte{}, FrameError{Err: ErrFrameTruncated, Expected: uint32(payloadLength), Actual: uint32(len(frameBytes) - headerLength)}
+	}
+	//--------------------
+	frameLength := headerLength + int(payloadLength) + 4
+	//------------------------------------------------------------
+	if len(frameBytes) < frameLength {
+		return []byte{}, FrameError{Err: ErrFrameTruncated, Expected: uint32(frameLength), Actual: uint32(len(frameBytes))}
+	}
+	//------------------------------------------------------------
+	expectedChecksum := binary.BigEndian.Uint32(frameBytes[frameLength-4 : frameLength])
+	actualChecksum := crc32.ChecksumIEEE(frameBytes[:frameLength-4])
+	//--------------------
+	if expectedChecksum != actualChecksum {
+		return []byte{}, FrameError{Err: ErrFrameChecksum, Expected: expectedChecksum, Actual: actualChecksum}
+	}
+	//--------------------
+	if len(frameBytes) != frameLength {
+		return []byte{}, FrameError{Err: ErrFrameTrailing, Expected: uint32(frameLength), Actual: uint32(len(frameBytes))}
+	}
+	//------------------------------------------------------------
+	return frameBytes[headerLength : headerLength+int(payloadLength)], nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,125 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"errors"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Frame_encode / Frame_decode
+//------------------------------------------------------------
+
+func TestFrame(t *testing.T) {
+	//------------------------------------------------------------
+	for _, dataString := range []string{"", "ABC <> &quot; £ 日本語\U0001f427"} {
+		for _, encoding := range []string{"base", "base64", "base91-escaped", "hex", "ascii85"} {
+			//--------------------
+			encodedString, err := Frame_encode(dataString, encoding)
+			//--------------------
+			if err != nil {
+				t.Fatal(err)
+			}
+			//--------------------
+			resultString, err := Frame_decode(encodedString+"\n", encoding)
+			//--------------------
+			if err != nil {
+				t.Errorf("(%q) %v", encoding, err)
+			} else if resultString != dataString {
+				t.Errorf("(%q) resultString = %q but should = %q", encoding, resultString, dataString)
+			}
+			//--------------------
+		}
+	}
+	//------------------------------------------------------------
+	if _, err := Frame_encode("ABC", "unknown"); err == nil {
+		t.Error("unknown encoding should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Frame_decode - errors
+//------------------------------------------------------------
+
+func TestFrame_decode_errors(t *testing.T) {
+	//------------------------------------------------------------
+	frameHex := Hex_encode(string(appendFrame(nil, []byte("ABCDEF"))))
+	//------------------------------------------------------------
+	testCases := []struct {
+		dataString  string
+		expectedErr error
+	}{
+		{"", ErrFrameTruncated},
+		{frameHex[:len(frameHex)-2], ErrFrameTruncated},
+		{frameHex[:8], ErrFrameTruncated},
+		{frameHex[:6] + "00" + frameHex[8:], ErrFrameChecksum},
+		{frameHex + "00", ErrFrameTrailing},
+		{"02" + frameHex[2:], ErrFrameVersion},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		var frameError FrameError
+		//--------------------
+		_, err := Frame_decode(testCase.dataString, "hex")
+		//--------------------
+		if !errors.As(err, &frameError) {
+			t.Errorf("(%q) err = %v but should be a FrameError", testCase.dataString, err)
+		} else if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("(%q) err = %v but should wrap %v", testCase.dataString, err, testCase.expectedErr)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	// trailing bytes report the frame length and the number of bytes received
+	var frameError FrameError
+	//--------------------
+	_, err := Frame_decode(frameHex+"0000", "hex")
+	//--------------------
+	if !errors.As(err, &frameError) || frameError.Err != ErrFrameTrailing || frameError.Expected != uint32(len(frameHex)/2) || frameError.Actual != uint32(len(frameHex)/2+2) {
+		t.Errorf("err = %#v but should = %v (expected %d bytes but got %d)", err, ErrFrameTrailing, len(frameHex)/2, len(frameHex)/2+2)
+	}
+	//------------------------------------------------------------
+	// invalid encoded data is reported by the underlying codec
+	var decodeError DecodeError
+	//--------------------
+	if _, err := Frame_decode("zz", "hex"); !errors.As(err, &decodeError) {
+		t.Errorf("err = %v but should be a DecodeError", err)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NewFrameCodec
+//------------------------------------------------------------
+
+func TestNewFrameCodec(t *testing.T) {
+	//------------------------------------------------------------
+	codec, _ := LookupCodec("base64url")
+	frameCodec := NewFrameCodec(codec)
+	//------------------------------------------------------------
+	encodedBytes := frameCodec.Encode([]byte("ABC"))
+	//--------------------
+	decodedBytes, err := frameCodec.Decode(encodedBytes)
+	//--------------------
+	if err != nil {
+		t.Error(err)
+	} else if string(decodedBytes) != "ABC" {
+		t.Errorf("decoded = %q but should = %q", string(decodedBytes), "ABC")
+	}
+	//------------------------------------------------------------
+	if _, err = frameCodec.Decode(encodedBytes[:len(encodedBytes)-3]); err == nil {
+		t.Error("truncated data should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,272 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// JSON_DecodeAs => json decodes jsonString into a value of type T
+//------------------------------------------------------------
+
+func JSON_DecodeAs[T any](jsonString string) (T, error) {
+	//------------------------------------------------------------
+	return jsonDecode[T](jsonString, false, false)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_DecodeStrict => as JSON_DecodeAs but rejects unknown struct fields and trailing data
+//------------------------------------------------------------
+
+func JSON_DecodeStrict[T any](jsonString string) (T, error) {
+	//------------------------------------------------------------
+	return jsonDecode[T](jsonString, true, false)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_DecodeNumber => as JSON_DecodeAs but numbers decoded into interfaces are kept as json.Number
+//------------------------------------------------------------
+
+func JSON_DecodeNumber[T any](jsonString string) (T, error) {
+	//------------------------------------------------------------
+	return jsonDecode[T](jsonString, false, true)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonDecode
+//------------------------------------------------------------
+
+func jsonDecode[T any](jsonString string, strictBool bool, numberBool bool) (T, error) {
+	//------------------------------------------------------------
+	var err error
+	var value T
+	//------------------------------------------------------------
+	decoder := json.NewDecoder(strings.NewReader(jsonString))
+	//--------------------
+	if strictBool {
+		decoder.DisallowUnknownFields()
+	}
+	//--------------------
+	if numberBool {
+		decoder.UseNumber()
+	}
+	//------------------------------------------------------------
+	err = decoder.Decode(&value)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	//--------------------
+	if err == nil {
+		//--------------------
+		// json.Unmarshal rejects anything after the first value so do the same here
+		if _, tokenErr := decoder.Token(); tokenErr != io.EOF {
+			err = errors.New("invalid character after top-level value")
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if err != nil {
+		var zeroValue T
+		return zeroValue, err
+	}
+	//------------------------------------------------------------
+	return value, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// JSON_Canonical => json encodes input using the JSON Canonicalization Scheme (RFC 8785)
+// so that the output is suitable for hashing and signing
+//------------------------------------------------------------
+
+func JSON_Canonical(input interface{}) ([]byte, error) {
+	//------------------------------------------------------------
+	// round trip through JSON_Marshal so that structs, json.RawMessage etc.
+	// are reduced to a plain tree first
+	jsonBytes, err := JSON_Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	jsonInterface, err := jsonDecode[any](string(jsonBytes), false, true)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	return appendCanonical(nil, jsonInterface)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendCanonical
+//------------------------------------------------------------
+
+func appendCanonical(dst []byte, value any) ([]byte, error) {
+	//------------------------------------------------------------
+	var err error
+	//------------------------------------------------------------
+	switch jsonValue := value.(type) {
+	case nil:
+		return append(dst, "null"...), nil
+	case bool:
+		return strconv.AppendBool(dst, jsonValue), nil
+	case string:
+		return appendCanonicalString(dst, jsonValue), nil
+	case json.Number:
+		//--------------------
+		number, err := strconv.ParseFloat(string(jsonValue), 64)
+		if err != nil {
+			return dst, fmt.Errorf("invalid number %s: %w", jsonValue, err)
+		}
+		//--------------------
+		return appendCanonicalNumber(dst, number)
+		//--------------------
+	case []any:
+		//--------------------
+		dst = append(dst, '[')
+		//--------------------
+		for index, element := range jsonValue {
+			if index > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, err = appendCanonical(dst, element); err != nil {
+				return dst, err
+			}
+		}
+		//--------------------
+		return append(dst, ']'), nil
+		//--------------------
+	case map[string]any:
+		//--------------------
+		// keys are sorted by their UTF-16 code units rather than their UTF-8 bytes
+		keys := make([]string, 0, len(jsonValue))
+		for key := range jsonValue {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, func(key1, key2 string) int {
+			return slices.Compare(utf16.Encode([]rune(key1)), utf16.Encode([]rune(key2)))
+		})
+		//--------------------
+		dst = append(dst, '{')
+		//--------------------
+		for index, key := range keys {
+			if index > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendCanonicalString(dst, key)
+			dst = append(dst, ':')
+			if dst, err = appendCanonical(dst, jsonValue[key]); err != nil {
+				return dst, err
+			}
+		}
+		//--------------------
+		return append(dst, '}'), nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return dst, fmt.Errorf("unsupported type %T", value)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendCanonicalNumber => formats number the same as ECMAScript Number.prototype.toString
+//------------------------------------------------------------
+
+func appendCanonicalNumber(dst []byte, number float64) ([]byte, error) {
+	//------------------------------------------------------------
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return dst, fmt.Errorf("number %v cannot be represented in json", number)
+	}
+	//------------------------------------------------------------
+	if number == 0 {
+		// also covers negative zero
+		return append(dst, '0'), nil
+	}
+	//------------------------------------------------------------
+	format := byte('e')
+	if math.Abs(number) >= 1e-6 && math.Abs(number) < 1e21 {
+		format = 'f'
+	}
+	//------------------------------------------------------------
+	numberBytes := strconv.AppendFloat(nil, number, format, -1, 64)
+	//------------------------------------------------------------
+	// Go writes exponents with at least two digits ("1e+09") but ECMAScript doesn't ("1e+9")
+	if exponentIndex := slices.Index(numberBytes, 'e'); exponentIndex > 0 && numberBytes[exponentIndex+2] == '0' {
+		numberBytes = slices.Delete(numberBytes, exponentIndex+2, exponentIndex+3)
+	}
+	//------------------------------------------------------------
+	return append(dst, numberBytes...), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendCanonicalString => only '"', '\\' and control characters are escaped
+//------------------------------------------------------------
+
+func appendCanonicalString(dst []byte, value string) []byte {
+	//------------------------------------------------------------
+	const hexChars = "0123456789abcdef"
+	//------------------------------------------------------------
+	dst = append(dst, '"')
+	//------------------------------------------------------------
+	for index := 0; index < len(value); index++ {
+		//--------------------
+		char := value[index]
+		//--------------------
+		switch char {
+		case '"', '\\':
+			dst = append(dst, '\\', char)
+		case '\b':
+			dst = append(dst, '\\', 'b')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\f':
+			dst = append(dst, '\\', 'f')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		default:
+			if char < 0x20 {
+				dst = append(dst, '\\', 'u', '0', '0', hexChars[char>>4], hexChars[char&0xF])
+			} else {
+				dst = append(dst, char)
+			}
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return append(dst, '"')
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
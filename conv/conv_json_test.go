@@ -0,0 +1,151 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+type jsonTestStruct struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// JSON_DecodeAs
+//------------------------------------------------------------
+
+func TestJSON_DecodeAs(t *testing.T) {
+	//------------------------------------------------------------
+	result, err := JSON_DecodeAs[jsonTestStruct](`{"id":123,"name":"<A&B>","extra":true}`)
+	//--------------------
+	if err != nil {
+		t.Error(err)
+	} else if result != (jsonTestStruct{123, "<A&B>"}) {
+		t.Errorf("result = %v but should = %v", result, jsonTestStruct{123, "<A&B>"})
+	}
+	//------------------------------------------------------------
+	resultMap, err := JSON_DecodeAs[map[string]any](`{"a":1}`)
+	//--------------------
+	if err != nil {
+		t.Error(err)
+	} else if resultMap["a"] != float64(1) {
+		t.Errorf("resultMap[\"a\"] = %v but should = %v", resultMap["a"], float64(1))
+	}
+	//------------------------------------------------------------
+	for _, jsonString := range []string{"", `{"id":"123"}`, `{"id":1} {}`, `{"id":1`} {
+		//--------------------
+		result, err := JSON_DecodeAs[jsonTestStruct](jsonString)
+		//--------------------
+		if err == nil {
+			t.Errorf("(%q) should return an error", jsonString)
+		} else if result != (jsonTestStruct{}) {
+			t.Errorf("(%q) result = %v but should be the zero value", jsonString, result)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_DecodeStrict
+//------------------------------------------------------------
+
+func TestJSON_DecodeStrict(t *testing.T) {
+	//------------------------------------------------------------
+	result, err := JSON_DecodeStrict[jsonTestStruct](" {\"id\":123,\"name\":\"ABC\"}\n")
+	//--------------------
+	if err != nil {
+		t.Error(err)
+	} else if result != (jsonTestStruct{123, "ABC"}) {
+		t.Errorf("result = %v but should = %v", result, jsonTestStruct{123, "ABC"})
+	}
+	//------------------------------------------------------------
+	for _, jsonString := range []string{`{"id":123,"extra":true}`, `{"id":123}{"id":456}`, `{"id":123} x`} {
+		if _, err := JSON_DecodeStrict[jsonTestStruct](jsonString); err == nil {
+			t.Errorf("(%q) should return an error", jsonString)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_DecodeNumber
+//------------------------------------------------------------
+
+func TestJSON_DecodeNumber(t *testing.T) {
+	//------------------------------------------------------------
+	jsonString := `{"html":"<&>","id":9007199254740993}`
+	//------------------------------------------------------------
+	resultMap, err := JSON_DecodeNumber[map[string]any](jsonString)
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	if resultMap["id"] != json.Number("9007199254740993") {
+		t.Errorf("resultMap[\"id\"] = %#v but should = %#v", resultMap["id"], json.Number("9007199254740993"))
+	}
+	//------------------------------------------------------------
+	// re-encoding should give back the original json without escaping html characters
+	resultString, err := JSON_encode(resultMap)
+	//--------------------
+	if err != nil {
+		t.Error(err)
+	} else if resultString != jsonString {
+		t.Errorf("resultString = %q but should = %q", resultString, jsonString)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_Canonical
+//------------------------------------------------------------
+
+func TestJSON_Canonical(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		input          any
+		expectedString string
+	}{
+		// RFC 8785 section 3.2.2
+		{json.RawMessage(`{"numbers":[333333333.33333329,1E30,4.50,2e-3,0.000000000000000000000000001],"string":"\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/","literals":[null,true,false]}`),
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`},
+		// RFC 8785 section 3.2.3 (sorted by UTF-16 code units)
+		{json.RawMessage(`{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`),
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"},
+		{map[string]any{"b": []any{1, 2.5, -0.0}, "a": "<&>"}, `{"a":"<&>","b":[1,2.5,0]}`},
+		{jsonTestStruct{ID: 1, Name: "x"}, `{"id":1,"name":"x"}`},
+		{[]float64{1e21, 1e20, 1e-6, 1e-7, 123e-20, -5e-324, 1.7976931348623157e308}, `[1e+21,100000000000000000000,0.000001,1e-7,1.23e-18,-5e-324,1.7976931348623157e+308]`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		resultBytes, err := JSON_Canonical(testCase.input)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if string(resultBytes) != testCase.expectedString {
+			t.Errorf("resultString = %q but should = %q", string(resultBytes), testCase.expectedString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if _, err := JSON_Canonical(json.RawMessage(`[1e400]`)); err == nil {
+		t.Error("number out of range should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
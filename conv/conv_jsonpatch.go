@@ -0,0 +1,755 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------
+// documents are the trees produced by JSON_decode, i.e. map[string]any,
+// []any, string, float64 (or json.Number), bool and nil
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// JSON_PointerGet => returns the value at pointer (RFC 6901)
+//------------------------------------------------------------
+
+func JSON_PointerGet(document any, pointer string) (any, error) {
+	//------------------------------------------------------------
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	value, err := jsonPointerGet(document, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("json pointer %q: %w", pointer, err)
+	}
+	//------------------------------------------------------------
+	return value, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_PointerSet => sets the value at pointer, replacing existing values and
+// creating object members, "-" (or the array length) appends to an array
+//
+// maps within document are modified in place so always use the returned document
+//------------------------------------------------------------
+
+func JSON_PointerSet(document any, pointer string, value any) (any, error) {
+	//------------------------------------------------------------
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return document, err
+	}
+	//------------------------------------------------------------
+	document, err = jsonPointerUpdate(document, tokens, func(parent any, token string) (any, error) {
+		//--------------------
+		if array, ok := parent.([]any); ok {
+			//--------------------
+			index, err := jsonArrayIndex(token, len(array), true)
+			if err != nil {
+				return parent, err
+			}
+			//--------------------
+			if index == len(array) {
+				return append(array, value), nil
+			}
+			//--------------------
+			array[index] = value
+			//--------------------
+			return array, nil
+		}
+		//--------------------
+		return jsonPointerAdd(parent, token, value)
+		//--------------------
+	}, value)
+	//------------------------------------------------------------
+	if err != nil {
+		return document, fmt.Errorf("json pointer %q: %w", pointer, err)
+	}
+	//------------------------------------------------------------
+	return document, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// JSON_PatchApply => applies a JSON Patch (RFC 6902) to a copy of document
+//
+// patch is a decoded patch document ([]any of map[string]any operations),
+// if any operation fails the error is returned along with the original document
+//------------------------------------------------------------
+
+func JSON_PatchApply(document any, patch any) (any, error) {
+	//------------------------------------------------------------
+	var operations []map[string]any
+	//------------------------------------------------------------
+	switch patchValue := patch.(type) {
+	case []map[string]any:
+		operations = patchValue
+	case []any:
+		for index, operation := range patchValue {
+			operationMap, ok := operation.(map[string]any)
+			if !ok {
+				return document, fmt.Errorf("json patch operation %d: operation must be an object", index)
+			}
+			operations = append(operations, operationMap)
+		}
+	default:
+		return document, errors.New("json patch must be an array of operations")
+	}
+	//------------------------------------------------------------
+	result := jsonCopy(document)
+	//------------------------------------------------------------
+	for index, operation := range operations {
+		//--------------------
+		var err error
+		//--------------------
+		result, err = jsonPatchOperation(result, operation)
+		if err != nil {
+			return document, fmt.Errorf("json patch operation %d (%v): %w", index, operation["op"], err)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return result, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_PatchDiff => returns a JSON Patch (RFC 6902) that turns source into target
+//------------------------------------------------------------
+
+func JSON_PatchDiff(source any, target any) []any {
+	//------------------------------------------------------------
+	patch := []any{}
+	//------------------------------------------------------------
+	jsonPatchDiff("", source, target, &patch)
+	//------------------------------------------------------------
+	return patch
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_MergePatch => applies a JSON Merge Patch (RFC 7386) and returns the result
+//
+// neither target nor patch are modified
+//------------------------------------------------------------
+
+func JSON_MergePatch(target any, patch any) any {
+	//------------------------------------------------------------
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return jsonCopy(patch)
+	}
+	//------------------------------------------------------------
+	resultMap := map[string]any{}
+	//--------------------
+	if targetMap, ok := target.(map[string]any); ok {
+		for key, value := range targetMap {
+			resultMap[key] = jsonCopy(value)
+		}
+	}
+	//------------------------------------------------------------
+	for key, value := range patchMap {
+		//--------------------
+		if value == nil {
+			delete(resultMap, key)
+		} else {
+			resultMap[key] = JSON_MergePatch(resultMap[key], value)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return resultMap
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// jsonPatchOperation
+//------------------------------------------------------------
+
+func jsonPatchOperation(document any, operation map[string]any) (any, error) {
+	//------------------------------------------------------------
+	op, _ := operation["op"].(string)
+	//--------------------
+	path, ok := operation["path"].(string)
+	if !ok {
+		return document, errors.New("missing path")
+	}
+	//--------------------
+	pathTokens, err := parseJSONPointer(path)
+	if err != nil {
+		return document, err
+	}
+	//------------------------------------------------------------
+	value, valueExists := operation["value"]
+	//--------------------
+	if !valueExists && (op == "add" || op == "replace" || op == "test") {
+		return document, errors.New("missing value")
+	}
+	//------------------------------------------------------------
+	var fromTokens []string
+	//--------------------
+	if op == "move" || op == "copy" {
+		//--------------------
+		from, ok := operation["from"].(string)
+		if !ok {
+			return document, errors.New("missing from")
+		}
+		//--------------------
+		fromTokens, err = parseJSONPointer(from)
+		if err != nil {
+			return document, err
+		}
+		//--------------------
+		value, err = jsonPointerGet(document, fromTokens)
+		if err != nil {
+			return document, err
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	switch op {
+	case "add":
+		//--------------------
+		// the result must not share maps or arrays with the patch
+		value = jsonCopy(value)
+		//--------------------
+		return jsonPointerUpdate(document, pathTokens, func(parent any, token string) (any, error) {
+			return jsonPointerAdd(parent, token, value)
+		}, value)
+		//--------------------
+	case "remove":
+		//--------------------
+		return jsonPointerRemove(document, pathTokens)
+		//--------------------
+	case "replace":
+		//--------------------
+		if _, err = jsonPointerGet(document, pathTokens); err != nil {
+			return document, err
+		}
+		//--------------------
+		value = jsonCopy(value)
+		//--------------------
+		return jsonPointerUpdate(document, pathTokens, func(parent any, token string) (any, error) {
+			return jsonPointerReplace(parent, token, value)
+		}, value)
+		//--------------------
+	case "move":
+		//--------------------
+		if len(fromTokens) < len(pathTokens) && slices.Equal(fromTokens, pathTokens[:len(fromTokens)]) {
+			return document, errors.New("cannot move a value into one of its children")
+		}
+		//--------------------
+		document, err = jsonPointerRemove(document, fromTokens)
+		if err != nil {
+			return document, err
+		}
+		//--------------------
+		return jsonPointerUpdate(document, pathTokens, func(parent any, token string) (any, error) {
+			return jsonPointerAdd(parent, token, value)
+		}, value)
+		//--------------------
+	case "copy":
+		//--------------------
+		value = jsonCopy(value)
+		//--------------------
+		return jsonPointerUpdate(document, pathTokens, func(parent any, token string) (any, error) {
+			return jsonPointerAdd(parent, token, value)
+		}, value)
+		//--------------------
+	case "test":
+		//--------------------
+		currentValue, err := jsonPointerGet(document, pathTokens)
+		if err != nil {
+			return document, err
+		}
+		//--------------------
+		if !jsonEqual(currentValue, value) {
+			return document, errors.New("test failed")
+		}
+		//--------------------
+		return document, nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return document, fmt.Errorf("unknown op: %v", operation["op"])
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonPatchDiff
+//------------------------------------------------------------
+
+func jsonPatchDiff(path string, source any, target any, patch *[]any) {
+	//------------------------------------------------------------
+	if jsonEqual(source, target) {
+		return
+	}
+	//------------------------------------------------------------
+	sourceMap, sourceIsMap := source.(map[string]any)
+	targetMap, targetIsMap := target.(map[string]any)
+	//--------------------
+	if sourceIsMap && targetIsMap {
+		//--------------------
+		sourceKeys := make([]string, 0, len(sourceMap))
+		for key := range sourceMap {
+			sourceKeys = append(sourceKeys, key)
+		}
+		slices.Sort(sourceKeys)
+		//--------------------
+		targetKeys := make([]string, 0, len(targetMap))
+		for key := range targetMap {
+			targetKeys = append(targetKeys, key)
+		}
+		slices.Sort(targetKeys)
+		//--------------------
+		for _, key := range sourceKeys {
+			if targetValue, exists := targetMap[key]; exists {
+				jsonPatchDiff(path+"/"+jsonPointerEscape(key), sourceMap[key], targetValue, patch)
+			} else {
+				*patch = append(*patch, map[string]any{"op": "remove", "path": path + "/" + jsonPointerEscape(key)})
+			}
+		}
+		//--------------------
+		for _, key := range targetKeys {
+			if _, exists := sourceMap[key]; !exists {
+				*patch = append(*patch, map[string]any{"op": "add", "path": path + "/" + jsonPointerEscape(key), "value": jsonCopy(targetMap[key])})
+			}
+		}
+		//--------------------
+		return
+	}
+	//------------------------------------------------------------
+	sourceArray, sourceIsArray := source.([]any)
+	targetArray, targetIsArray := target.([]any)
+	//--------------------
+	if sourceIsArray && targetIsArray {
+		//--------------------
+		commonLength := min(len(sourceArray), len(targetArray))
+		//--------------------
+		for index := 0; index < commonLength; index++ {
+			jsonPatchDiff(path+"/"+strconv.Itoa(index), sourceArray[index], targetArray[index], patch)
+		}
+		//--------------------
+		// remove from the end so earlier indexes stay valid
+		for index := len(sourceArray) - 1; index >= commonLength; index-- {
+			*patch = append(*patch, map[string]any{"op": "remove", "path": path + "/" + strconv.Itoa(index)})
+		}
+		//--------------------
+		for index := commonLength; index < len(targetArray); index++ {
+			*patch = append(*patch, map[string]any{"op": "add", "path": path + "/" + strconv.Itoa(index), "value": jsonCopy(targetArray[index])})
+		}
+		//--------------------
+		return
+	}
+	//------------------------------------------------------------
+	*patch = append(*patch, map[string]any{"op": "replace", "path": path, "value": jsonCopy(target)})
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// parseJSONPointer
+//------------------------------------------------------------
+
+func parseJSONPointer(pointer string) ([]string, error) {
+	//------------------------------------------------------------
+	if pointer == "" {
+		return []string{}, nil
+	}
+	//------------------------------------------------------------
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("json pointer %q must be empty or start with \"/\"", pointer)
+	}
+	//------------------------------------------------------------
+	tokens := strings.Split(pointer[1:], "/")
+	//------------------------------------------------------------
+	for index, token := range tokens {
+		//--------------------
+		for charIndex := 0; charIndex < len(token); charIndex++ {
+			if token[charIndex] == '~' && (charIndex+1 == len(token) || (token[charIndex+1] != '0' && token[charIndex+1] != '1')) {
+				return nil, fmt.Errorf("json pointer %q contains an invalid escape sequence", pointer)
+			}
+		}
+		//--------------------
+		tokens[index] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return tokens, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonPointerEscape
+//------------------------------------------------------------
+
+func jsonPointerEscape(token string) string {
+	//------------------------------------------------------------
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonArrayIndex => "-" (or length when appendBool) returns the array length
+//------------------------------------------------------------
+
+func jsonArrayIndex(token string, length int, appendBool bool) (int, error) {
+	//------------------------------------------------------------
+	if token == "-" {
+		//--------------------
+		if appendBool {
+			return length, nil
+		}
+		//--------------------
+		return 0, errors.New("array index \"-\" is not valid here")
+	}
+	//------------------------------------------------------------
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	//------------------------------------------------------------
+	index, err := strconv.Atoi(token)
+	//--------------------
+	maxIndex := length - 1
+	if appendBool {
+		maxIndex = length
+	}
+	//--------------------
+	if err != nil || index > maxIndex {
+		return 0, fmt.Errorf("array index %s out of range", token)
+	}
+	//------------------------------------------------------------
+	return index, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonPointerGet
+//------------------------------------------------------------
+
+func jsonPointerGet(document any, tokens []string) (any, error) {
+	//------------------------------------------------------------
+	value := document
+	//------------------------------------------------------------
+	for _, token := range tokens {
+		//--------------------
+		switch container := value.(type) {
+		case map[string]any:
+			//--------------------
+			memberValue, exists := container[token]
+			if !exists {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			//--------------------
+			value = memberValue
+			//--------------------
+		case []any:
+			//--------------------
+			index, err := jsonArrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			//--------------------
+			value = container[index]
+			//--------------------
+		default:
+			return nil, fmt.Errorf("cannot reference %q in a non-container value", token)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return value, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonPointerUpdate => calls updateFunc with the parent container and last token,
+// the root document is replaced with rootValue when tokens is empty
+//------------------------------------------------------------
+
+func jsonPointerUpdate(document any, tokens []string, updateFunc func(parent any, token string) (any, error), rootValue any) (any, error) {
+	//------------------------------------------------------------
+	if len(tokens) == 0 {
+		return rootValue, nil
+	}
+	//------------------------------------------------------------
+	if len(tokens) == 1 {
+		return updateFunc(document, tokens[0])
+	}
+	//------------------------------------------------------------
+	child, err := jsonPointerGet(document, tokens[:1])
+	if err != nil {
+		return document, err
+	}
+	//--------------------
+	child, err = jsonPointerUpdate(child, tokens[1:], updateFunc, rootValue)
+	if err != nil {
+		return document, err
+	}
+	//------------------------------------------------------------
+	// child slices may have grown or shrunk so store them back in their parent
+	return jsonPointerReplace(document, tokens[0], child)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonPointerAdd => RFC 6902 "add" (array values are inserted)
+//------------------------------------------------------------
+
+func jsonPointerAdd(parent any, token string, value any) (any, error) {
+	//------------------------------------------------------------
+	switch container := parent.(type) {
+	case map[string]any:
+		//--------------------
+		container[token] = value
+		//--------------------
+		return container, nil
+		//--------------------
+	case []any:
+		//--------------------
+		index, err := jsonArrayIndex(token, len(container), true)
+		if err != nil {
+			return parent, err
+		}
+		//--------------------
+		return slices.Insert(container, index, value), nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return parent, fmt.Errorf("cannot add %q to a non-container value", token)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonPointerReplace
+//------------------------------------------------------------
+
+func jsonPointerReplace(parent any, token string, value any) (any, error) {
+	//------------------------------------------------------------
+	switch container := parent.(type) {
+	case map[string]any:
+		//--------------------
+		if _, exists := container[token]; !exists {
+			return parent, fmt.Errorf("member %q not found", token)
+		}
+		//--------------------
+		container[token] = value
+		//--------------------
+		return container, nil
+		//--------------------
+	case []any:
+		//--------------------
+		index, err := jsonArrayIndex(token, len(container), false)
+		if err != nil {
+			return parent, err
+		}
+		//--------------------
+		container[index] = value
+		//--------------------
+		return container, nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return parent, fmt.Errorf("cannot replace %q in a non-container value", token)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonPointerRemove
+//------------------------------------------------------------
+
+func jsonPointerRemove(document any, tokens []string) (any, error) {
+	//------------------------------------------------------------
+	if len(tokens) == 0 {
+		return document, errors.New("cannot remove the root document")
+	}
+	//------------------------------------------------------------
+	return jsonPointerUpdate(document, tokens, func(parent any, token string) (any, error) {
+		//--------------------
+		switch container := parent.(type) {
+		case map[string]any:
+			//--------------------
+			if _, exists := container[token]; !exists {
+				return parent, fmt.Errorf("member %q not found", token)
+			}
+			//--------------------
+			delete(container, token)
+			//--------------------
+			return container, nil
+			//--------------------
+		case []any:
+			//--------------------
+			index, err := jsonArrayIndex(token, len(container), false)
+			if err != nil {
+				return parent, err
+			}
+			//--------------------
+			return slices.Delete(container, index, index+1), nil
+			//--------------------
+		}
+		//--------------------
+		return parent, fmt.Errorf("cannot remove %q from a non-container value", token)
+		//--------------------
+	}, nil)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// jsonCopy => deep copies maps and arrays within a document
+//------------------------------------------------------------
+
+func jsonCopy(value any) any {
+	//------------------------------------------------------------
+	switch container := value.(type) {
+	case map[string]any:
+		//--------------------
+		copyMap := make(map[string]any, len(container))
+		for key, memberValue := range container {
+			copyMap[key] = jsonCopy(memberValue)
+		}
+		//--------------------
+		return copyMap
+		//--------------------
+	case []any:
+		//--------------------
+		copyArray := make([]any, len(container))
+		for index, element := range container {
+			copyArray[index] = jsonCopy(element)
+		}
+		//--------------------
+		return copyArray
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return value
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonEqual => compares documents with numbers compared by value
+//------------------------------------------------------------
+
+func jsonEqual(value1 any, value2 any) bool {
+	//------------------------------------------------------------
+	switch container1 := value1.(type) {
+	case map[string]any:
+		//--------------------
+		container2, ok := value2.(map[string]any)
+		if !ok || len(container1) != len(container2) {
+			return false
+		}
+		//--------------------
+		for key, memberValue := range container1 {
+			if memberValue2, exists := container2[key]; !exists || !jsonEqual(memberValue, memberValue2) {
+				return false
+			}
+		}
+		//--------------------
+		return true
+		//--------------------
+	case []any:
+		//--------------------
+		container2, ok := value2.([]any)
+		if !ok || len(container1) != len(container2) {
+			return false
+		}
+		//--------------------
+		for index := range container1 {
+			if !jsonEqual(container1[index], container2[index]) {
+				return false
+			}
+		}
+		//--------------------
+		return true
+		//--------------------
+	}
+	//------------------------------------------------------------
+	number1, isNumber1 := jsonNumberRat(value1)
+	number2, isNumber2 := jsonNumberRat(value2)
+	//--------------------
+	if isNumber1 || isNumber2 {
+		return isNumber1 && isNumber2 && number1.Cmp(number2) == 0
+	}
+	//------------------------------------------------------------
+	return reflect.DeepEqual(value1, value2)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// jsonNumberRat
+//------------------------------------------------------------
+
+func jsonNumberRat(value any) (*big.Rat, bool) {
+	//------------------------------------------------------------
+	switch number := value.(type) {
+	case float64:
+		if rat := new(big.Rat); rat.SetFloat64(number) != nil {
+			return rat, true
+		}
+	case float32:
+		if rat := new(big.Rat); rat.SetFloat64(float64(number)) != nil {
+			return rat, true
+		}
+	case json.Number:
+		if rat, ok := new(big.Rat).SetString(string(number)); ok {
+			return rat, true
+		}
+	case int:
+		return new(big.Rat).SetInt64(int64(number)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(number)), true
+	case int64:
+		return new(big.Rat).SetInt64(number), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(number)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(number)), true
+	case uint64:
+		return new(big.Rat).SetUint64(number), true
+	}
+	//------------------------------------------------------------
+	return nil, false
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,323 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+func mustJSON_decode(t *testing.T, jsonString string) any {
+	//------------------------------------------------------------
+	t.Helper()
+	//------------------------------------------------------------
+	jsonInterface, err := JSON_decode(jsonString)
+	if err != nil {
+		t.Fatalf("(%q) %v", jsonString, err)
+	}
+	//------------------------------------------------------------
+	return jsonInterface
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// JSON_PointerGet
+//------------------------------------------------------------
+
+func TestJSON_PointerGet(t *testing.T) {
+	//------------------------------------------------------------
+	// RFC 6901 section 5
+	document := mustJSON_decode(t, `{"foo":["bar","baz"],"":0,"a/b":1,"c%d":2,"e^f":3,"g|h":4,"i\\j":5,"k\"l":6," ":7,"m~n":8}`)
+	//------------------------------------------------------------
+	testCases := []struct {
+		pointer        string
+		expectedString string
+	}{
+		{"", `{"":0," ":7,"a/b":1,"c%d":2,"e^f":3,"foo":["bar","baz"],"g|h":4,"i\\j":5,"k\"l":6,"m~n":8}`},
+		{"/foo", `["bar","baz"]`},
+		{"/foo/0", `"bar"`},
+		{"/", `0`},
+		{"/a~1b", `1`},
+		{"/c%d", `2`},
+		{"/i\\j", `5`},
+		{"/ ", `7`},
+		{"/m~0n", `8`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		value, err := JSON_PointerGet(document, testCase.pointer)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%q) %v", testCase.pointer, err)
+			continue
+		}
+		//--------------------
+		resultString, _ := JSON_encode(value)
+		//--------------------
+		if resultString != testCase.expectedString {
+			t.Errorf("(%q) resultString = %q but should = %q", testCase.pointer, resultString, testCase.expectedString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	for _, pointer := range []string{"foo", "/missing", "/foo/2", "/foo/01", "/foo/-", "/foo/0/x", "/m~2n"} {
+		if _, err := JSON_PointerGet(document, pointer); err == nil {
+			t.Errorf("(%q) should return an error", pointer)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_PointerSet
+//------------------------------------------------------------
+
+func TestJSON_PointerSet(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		pointer        string
+		expectedString string
+	}{
+		{"/a", `{"a":"X","b":{"c":[1,2]}}`},
+		{"/d", `{"a":1,"b":{"c":[1,2]},"d":"X"}`},
+		{"/b/c/0", `{"a":1,"b":{"c":["X",2]}}`},
+		{"/b/c/-", `{"a":1,"b":{"c":[1,2,"X"]}}`},
+		{"/b/c/2", `{"a":1,"b":{"c":[1,2,"X"]}}`},
+		{"", `"X"`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		document := mustJSON_decode(t, `{"a":1,"b":{"c":[1,2]}}`)
+		//--------------------
+		document, err := JSON_PointerSet(document, testCase.pointer, "X")
+		//--------------------
+		if err != nil {
+			t.Errorf("(%q) %v", testCase.pointer, err)
+			continue
+		}
+		//--------------------
+		resultString, _ := JSON_encode(document)
+		//--------------------
+		if resultString != testCase.expectedString {
+			t.Errorf("(%q) resultString = %q but should = %q", testCase.pointer, resultString, testCase.expectedString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	for _, pointer := range []string{"/x/y", "/b/c/3", "/a/b"} {
+		if _, err := JSON_PointerSet(mustJSON_decode(t, `{"a":1,"b":{"c":[1,2]}}`), pointer, "X"); err == nil {
+			t.Errorf("(%q) should return an error", pointer)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_PatchApply
+//------------------------------------------------------------
+
+func TestJSON_PatchApply(t *testing.T) {
+	//------------------------------------------------------------
+	// mostly taken from RFC 6902 appendix A
+	testCases := []struct {
+		documentString string
+		patchString    string
+		expectedString string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"child":{"grandchild":{}},"foo":"bar"}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{`{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		document, err := JSON_PatchApply(mustJSON_decode(t, testCase.documentString), mustJSON_decode(t, testCase.patchString))
+		//--------------------
+		if err != nil {
+			t.Errorf("(%s) %v", testCase.patchString, err)
+			continue
+		}
+		//--------------------
+		resultString, _ := JSON_encode(document)
+		//--------------------
+		if resultString != testCase.expectedString {
+			t.Errorf("(%s) resultString = %q but should = %q", testCase.patchString, resultString, testCase.expectedString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	errorCases := []struct {
+		documentString string
+		patchString    string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`},
+		{`{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar/x"}]`},
+		{`{"foo":"bar"}`, `[{"op":"invalid","path":"/foo"}]`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/foo"}]`},
+		{`{"foo":"bar"}`, `{"op":"add","path":"/foo","value":1}`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range errorCases {
+		//--------------------
+		document := mustJSON_decode(t, testCase.documentString)
+		//--------------------
+		resultDocument, err := JSON_PatchApply(document, mustJSON_decode(t, testCase.patchString))
+		//--------------------
+		if err == nil {
+			t.Errorf("(%s) should return an error", testCase.patchString)
+		}
+		//--------------------
+		resultString, _ := JSON_encode(resultDocument)
+		//--------------------
+		if resultString != testCase.documentString {
+			t.Errorf("(%s) resultString = %q but should = %q", testCase.patchString, resultString, testCase.documentString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	// a failed patch must not partially modify the original document
+	document := mustJSON_decode(t, `{"a":1}`)
+	//--------------------
+	_, _ = JSON_PatchApply(document, mustJSON_decode(t, `[{"op":"add","path":"/b","value":2},{"op":"remove","path":"/x"}]`))
+	//--------------------
+	if resultString, _ := JSON_encode(document); resultString != `{"a":1}` {
+		t.Errorf("resultString = %q but should = %q", resultString, `{"a":1}`)
+	}
+	//------------------------------------------------------------
+	// values added from the patch must not be shared with the result
+	patchString := `[{"op":"add","path":"/b","value":{"c":[1]}},{"op":"replace","path":"/a","value":[1]}]`
+	patch := mustJSON_decode(t, patchString)
+	//--------------------
+	resultDocument, err := JSON_PatchApply(document, patch)
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	resultMap := resultDocument.(map[string]any)
+	resultMap["b"].(map[string]any)["c"].([]any)[0] = "changed"
+	resultMap["a"].([]any)[0] = "changed"
+	//--------------------
+	if resultString, _ := JSON_encode(patch); resultString != patchString {
+		t.Errorf("patch = %q but should = %q", resultString, patchString)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_PatchDiff
+//------------------------------------------------------------
+
+func TestJSON_PatchDiff(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		sourceString string
+		targetString string
+	}{
+		{`{"a":1,"b":{"c":[1,2,3]},"d":"x"}`, `{"a":1,"b":{"c":[1,5]},"e/f":{"g":null}}`},
+		{`{"a":[1]}`, `{"a":[1,2,{"b":3}]}`},
+		{`[1,2]`, `{"a":1}`},
+		{`{"a":1}`, `{"a":1}`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		source := mustJSON_decode(t, testCase.sourceString)
+		target := mustJSON_decode(t, testCase.targetString)
+		//--------------------
+		patch := JSON_PatchDiff(source, target)
+		//--------------------
+		document, err := JSON_PatchApply(source, patch)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%s) %v", testCase.targetString, err)
+			continue
+		}
+		//--------------------
+		resultString, _ := JSON_encode(document)
+		//--------------------
+		if resultString != testCase.targetString {
+			t.Errorf("resultString = %q but should = %q", resultString, testCase.targetString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	patchString, _ := JSON_encode(JSON_PatchDiff(mustJSON_decode(t, `{"a":1,"b":2}`), mustJSON_decode(t, `{"a":1,"b":3}`)))
+	//--------------------
+	if patchString != `[{"op":"replace","path":"/b","value":3}]` {
+		t.Errorf("patchString = %q but should = %q", patchString, `[{"op":"replace","path":"/b","value":3}]`)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// JSON_MergePatch
+//------------------------------------------------------------
+
+func TestJSON_MergePatch(t *testing.T) {
+	//------------------------------------------------------------
+	// RFC 7386 appendix A
+	testCases := []struct {
+		targetString   string
+		patchString    string
+		expectedString string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		target := mustJSON_decode(t, testCase.targetString)
+		//--------------------
+		resultString, _ := JSON_encode(JSON_MergePatch(target, mustJSON_decode(t, testCase.patchString)))
+		//--------------------
+		if resultString != testCase.expectedString {
+			t.Errorf("(%s, %s) resultString = %q but should = %q", testCase.targetString, testCase.patchString, resultString, testCase.expectedString)
+		}
+		//--------------------
+		if targetString, _ := JSON_encode(target); targetString != testCase.targetString {
+			t.Errorf("target was modified: %q", targetString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
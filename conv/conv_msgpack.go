@@ -0,0 +1,562 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+//------------------------------------------------------------
+// MessagePack for the same trees JSON_decode produces
+//------------------------------------------------------------
+
+type msgpackDecoder struct {
+	data   []byte
+	offset int
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// MsgPack_Marshal => encodes input as MessagePack, map keys are written in sorted order
+// and whole numbers are written as integers
+//------------------------------------------------------------
+
+func MsgPack_Marshal(input interface{}) ([]byte, error) {
+	//------------------------------------------------------------
+	return appendMsgPack(nil, input, 0)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// MsgPack_Unmarshal => decodes MessagePack into map[string]any / []any trees
+//
+// integers are decoded as int64 (uint64 above math.MaxInt64), floats as float64,
+// bin as []byte and extension types return an error
+//------------------------------------------------------------
+
+func MsgPack_Unmarshal(data []byte) (interface{}, error) {
+	//------------------------------------------------------------
+	decoder := msgpackDecoder{data: data}
+	//------------------------------------------------------------
+	value, err := decoder.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	if decoder.offset != len(data) {
+		return nil, decoder.error("unexpected data after top-level value")
+	}
+	//------------------------------------------------------------
+	return value, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// appendMsgPack
+//------------------------------------------------------------
+
+func appendMsgPack(dst []byte, input any, depth int) ([]byte, error) {
+	//------------------------------------------------------------
+	var err error
+	//------------------------------------------------------------
+	if depth > maxTreeDepth {
+		return dst, errors.New("msgpack: maximum nesting depth exceeded")
+	}
+	//------------------------------------------------------------
+	switch value := input.(type) {
+	case nil:
+		return append(dst, 0xc0), nil
+	case bool:
+		if value {
+			return append(dst, 0xc3), nil
+		}
+		return append(dst, 0xc2), nil
+	case string:
+		return appendMsgPackString(dst, value)
+	case []byte:
+		//--------------------
+		switch {
+		case len(value) <= math.MaxUint8:
+			dst = append(dst, 0xc4, byte(len(value)))
+		case len(value) <= math.MaxUint16:
+			dst = binary.BigEndian.AppendUint16(append(dst, 0xc5), uint16(len(value)))
+		case uint64(len(value)) <= math.MaxUint32:
+			dst = binary.BigEndian.AppendUint32(append(dst, 0xc6), uint32(len(value)))
+		default:
+			return dst, errors.New("msgpack: bin too long")
+		}
+		//--------------------
+		return append(dst, value...), nil
+		//--------------------
+	case int:
+		return appendMsgPackInt(dst, int64(value)), nil
+	case int8:
+		return appendMsgPackInt(dst, int64(value)), nil
+	case int16:
+		return appendMsgPackInt(dst, int64(value)), nil
+	case int32:
+		return appendMsgPackInt(dst, int64(value)), nil
+	case int64:
+		return appendMsgPackInt(dst, value), nil
+	case uint:
+		return appendMsgPackUint(dst, uint64(value)), nil
+	case uint8:
+		return appendMsgPackUint(dst, uint64(value)), nil
+	case uint16:
+		return appendMsgPackUint(dst, uint64(value)), nil
+	case uint32:
+		return appendMsgPackUint(dst, uint64(value)), nil
+	case uint64:
+		return appendMsgPackUint(dst, value), nil
+	case float32:
+		return appendMsgPackFloat(dst, float64(value)), nil
+	case float64:
+		return appendMsgPackFloat(dst, value), nil
+	case json.Number:
+		//--------------------
+		number, err := treeNumber(value)
+		if err != nil {
+			return dst, err
+		}
+		//--------------------
+		return appendMsgPack(dst, number, depth)
+		//--------------------
+	case []any:
+		//--------------------
+		if dst, err = appendMsgPackLength(dst, len(value), 0x90, 0xdc); err != nil {
+			return dst, err
+		}
+		//--------------------
+		for _, element := range value {
+			if dst, err = appendMsgPack(dst, element, depth+1); err != nil {
+				return dst, err
+			}
+		}
+		//--------------------
+		return dst, nil
+		//--------------------
+	case map[string]any:
+		//--------------------
+		if dst, err = appendMsgPackLength(dst, len(value), 0x80, 0xde); err != nil {
+			return dst, err
+		}
+		//--------------------
+		for _, key := range sortedKeys(value) {
+			if dst, err = appendMsgPackString(dst, key); err != nil {
+				return dst, err
+			}
+			if dst, err = appendMsgPack(dst, value[key], depth+1); err != nil {
+				return dst, err
+			}
+		}
+		//--------------------
+		return dst, nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	treeInput, err := treeValue(input)
+	if err != nil {
+		return dst, err
+	}
+	//------------------------------------------------------------
+	return appendMsgPack(dst, treeInput, depth)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendMsgPackString
+//------------------------------------------------------------
+
+func appendMsgPackString(dst []byte, value string) ([]byte, error) {
+	//------------------------------------------------------------
+	switch {
+	case len(value) < 32:
+		dst = append(dst, 0xa0|byte(len(value)))
+	case len(value) <= math.MaxUint8:
+		dst = append(dst, 0xd9, byte(len(value)))
+	case len(value) <= math.MaxUint16:
+		dst = binary.BigEndian.AppendUint16(append(dst, 0xda), uint16(len(value)))
+	case uint64(len(value)) <= math.MaxUint32:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xdb), uint32(len(value)))
+	default:
+		return dst, errors.New("msgpack: string too long")
+	}
+	//------------------------------------------------------------
+	return append(dst, value...), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendMsgPackLength => array / map length (fixPrefix for up to 15 entries then 16 / 32 bit)
+//------------------------------------------------------------
+
+func appendMsgPackLength(dst []byte, length int, fixPrefix byte, prefix16 byte) ([]byte, error) {
+	//------------------------------------------------------------
+	switch {
+	case length < 16:
+		return append(dst, fixPrefix|byte(length)), nil
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, prefix16), uint16(length)), nil
+	case uint64(length) <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, prefix16+1), uint32(length)), nil
+	}
+	//------------------------------------------------------------
+	return dst, errors.New("msgpack: too many elements")
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendMsgPackUint
+//------------------------------------------------------------
+
+func appendMsgPackUint(dst []byte, value uint64) []byte {
+	//------------------------------------------------------------
+	switch {
+	case value <= 0x7f:
+		return append(dst, byte(value))
+	case value <= math.MaxUint8:
+		return append(dst, 0xcc, byte(value))
+	case value <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(value))
+	case value <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(value))
+	}
+	//------------------------------------------------------------
+	return binary.BigEndian.AppendUint64(append(dst, 0xcf), value)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendMsgPackInt
+//------------------------------------------------------------
+
+func appendMsgPackInt(dst []byte, value int64) []byte {
+	//------------------------------------------------------------
+	switch {
+	case value >= 0:
+		return appendMsgPackUint(dst, uint64(value))
+	case value >= -32:
+		return append(dst, byte(value))
+	case value >= math.MinInt8:
+		return append(dst, 0xd0, byte(value))
+	case value >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(value))
+	case value >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(value))
+	}
+	//------------------------------------------------------------
+	return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(value))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendMsgPackFloat
+//------------------------------------------------------------
+
+func appendMsgPackFloat(dst []byte, value float64) []byte {
+	//------------------------------------------------------------
+	if intValue, ok := floatAsInt(value); ok {
+		return appendMsgPackInt(dst, intValue)
+	}
+	//------------------------------------------------------------
+	if float64(float32(value)) == value || math.IsInf(value, 0) {
+		return binary.BigEndian.AppendUint32(append(dst, 0xca), math.Float32bits(float32(value)))
+	}
+	//------------------------------------------------------------
+	return binary.BigEndian.AppendUint64(append(dst, 0xcb), math.Float64bits(value))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// msgpackDecoder - error
+//------------------------------------------------------------
+
+func (decoder *msgpackDecoder) error(reason string) error {
+	//------------------------------------------------------------
+	return DecodeError{Encoding: "msgpack", Offset: int64(decoder.offset), Reason: reason}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// msgpackDecoder - read => returns the next n bytes
+//------------------------------------------------------------
+
+func (decoder *msgpackDecoder) read(n uint64) ([]byte, error) {
+	//------------------------------------------------------------
+	if n > uint64(len(decoder.data)-decoder.offset) {
+		return nil, decoder.error("unexpected end of data")
+	}
+	//------------------------------------------------------------
+	dataBytes := decoder.data[decoder.offset : decoder.offset+int(n)]
+	decoder.offset += int(n)
+	//------------------------------------------------------------
+	return dataBytes, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// msgpackDecoder - readUint => big endian unsigned integer of size bytes
+//------------------------------------------------------------
+
+func (decoder *msgpackDecoder) readUint(size uint64) (uint64, error) {
+	//------------------------------------------------------------
+	dataBytes, err := decoder.read(size)
+	if err != nil {
+		return 0, err
+	}
+	//------------------------------------------------------------
+	var value uint64
+	//--------------------
+	for _, dataByte := range dataBytes {
+		value = value<<8 | uint64(dataByte)
+	}
+	//------------------------------------------------------------
+	return value, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// msgpackDecoder - decode
+//------------------------------------------------------------
+
+func (decoder *msgpackDecoder) decode(depth int) (any, error) {
+	//------------------------------------------------------------
+	if depth > maxTreeDepth {
+		return nil, decoder.error("maximum nesting depth exceeded")
+	}
+	//------------------------------------------------------------
+	typeOffset := decoder.offset
+	//--------------------
+	typeBytes, err := decoder.read(1)
+	if err != nil {
+		return nil, err
+	}
+	//--------------------
+	typeByte := typeBytes[0]
+	//------------------------------------------------------------
+	switch {
+	case typeByte <= 0x7f:
+		return int64(typeByte), nil
+	case typeByte >= 0xe0:
+		return int64(int8(typeByte)), nil
+	case typeByte <= 0x8f:
+		return decoder.decodeMap(uint64(typeByte&0x0f), depth)
+	case typeByte <= 0x9f:
+		return decoder.decodeArray(uint64(typeByte&0x0f), depth)
+	case typeByte <= 0xbf:
+		return decoder.decodeString(uint64(typeByte & 0x1f))
+	}
+	//------------------------------------------------------------
+	var length uint64
+	//------------------------------------------------------------
+	switch typeByte {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		//--------------------
+		if length, err = decoder.readUint(1 << (typeByte - 0xc4)); err != nil {
+			return nil, err
+		}
+		//--------------------
+		binBytes, err := decoder.read(length)
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		return append([]byte{}, binBytes...), nil
+		//--------------------
+	case 0xca:
+		//--------------------
+		bits, err := decoder.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		return float64(math.Float32frombits(uint32(bits))), nil
+		//--------------------
+	case 0xcb:
+		//--------------------
+		bits, err := decoder.readUint(8)
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		return math.Float64frombits(bits), nil
+		//--------------------
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		//--------------------
+		value, err := decoder.readUint(1 << (typeByte - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		if value > math.MaxInt64 {
+			return value, nil
+		}
+		//--------------------
+		return int64(value), nil
+		//--------------------
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		//--------------------
+		size := uint64(1) << (typeByte - 0xd0)
+		//--------------------
+		value, err := decoder.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		// sign extend
+		shift := 64 - size*8
+		//--------------------
+		return int64(value<<shift) >> shift, nil
+		//--------------------
+	case 0xd9, 0xda, 0xdb:
+		//--------------------
+		if length, err = decoder.readUint(1 << (typeByte - 0xd9)); err != nil {
+			return nil, err
+		}
+		//--------------------
+		return decoder.decodeString(length)
+		//--------------------
+	case 0xdc, 0xdd:
+		//--------------------
+		if length, err = decoder.readUint(2 << (typeByte - 0xdc)); err != nil {
+			return nil, err
+		}
+		//--------------------
+		return decoder.decodeArray(length, depth)
+		//--------------------
+	case 0xde, 0xdf:
+		//--------------------
+		if length, err = decoder.readUint(2 << (typeByte - 0xde)); err != nil {
+			return nil, err
+		}
+		//--------------------
+		return decoder.decodeMap(length, depth)
+		//--------------------
+	}
+	//------------------------------------------------------------
+	decoder.offset = typeOffset
+	//------------------------------------------------------------
+	return nil, decoder.error(fmt.Sprintf("unsupported type 0x%02x", typeByte))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// msgpackDecoder - decodeString
+//------------------------------------------------------------
+
+func (decoder *msgpackDecoder) decodeString(length uint64) (any, error) {
+	//------------------------------------------------------------
+	stringOffset := decoder.offset
+	//------------------------------------------------------------
+	stringBytes, err := decoder.read(length)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	if !utf8.Valid(stringBytes) {
+		decoder.offset = stringOffset
+		return nil, decoder.error("invalid UTF-8 in string")
+	}
+	//------------------------------------------------------------
+	return string(stringBytes), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// msgpackDecoder - decodeArray
+//------------------------------------------------------------
+
+func (decoder *msgpackDecoder) decodeArray(length uint64, depth int) (any, error) {
+	//------------------------------------------------------------
+	// every element takes at least one byte
+	if length > uint64(len(decoder.data)-decoder.offset) {
+		return nil, decoder.error("unexpected end of data")
+	}
+	//------------------------------------------------------------
+	array := make([]any, 0, length)
+	//------------------------------------------------------------
+	for index := uint64(0); index < length; index++ {
+		//--------------------
+		element, err := decoder.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		array = append(array, element)
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return array, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// msgpackDecoder - decodeMap
+//------------------------------------------------------------
+
+func (decoder *msgpackDecoder) decodeMap(length uint64, depth int) (any, error) {
+	//------------------------------------------------------------
+	// every entry takes at least two bytes
+	if length > uint64(len(decoder.data)-decoder.offset)/2 {
+		return nil, decoder.error("unexpected end of data")
+	}
+	//------------------------------------------------------------
+	valueMap := make(map[string]any, length)
+	//------------------------------------------------------------
+	for index := uint64(0); index < length; index++ {
+		//--------------------
+		keyOffset := decoder.offset
+		//--------------------
+		key, err := decoder.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		keyString, ok := key.(string)
+		if !ok {
+			decoder.offset = keyOffset
+			return nil, decoder.error("map keys must be strings")
+		}
+		//--------------------
+		if valueMap[keyString], err = decoder.decode(depth + 1); err != nil {
+			return nil, err
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return valueMap, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,138 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// MsgPack_Marshal
+//------------------------------------------------------------
+
+func TestMsgPack_Marshal(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		input       any
+		expectedHex string
+	}{
+		{0, "00"},
+		{127, "7f"},
+		{128, "cc80"},
+		{65536, "ce00010000"},
+		{uint64(18446744073709551615), "cfffffffffffffffff"},
+		{-1, "ff"},
+		{-32, "e0"},
+		{-33, "d0df"},
+		{-32769, "d2ffff7fff"},
+		{float64(2), "02"},
+		{1.5, "ca3fc00000"},
+		{1.1, "cb3ff199999999999a"},
+		{nil, "c0"},
+		{false, "c2"},
+		{true, "c3"},
+		{"", "a0"},
+		{"abc", "a3616263"},
+		{strings.Repeat("a", 32), "d920" + strings.Repeat("61", 32)},
+		{[]byte{1, 2}, "c4020102"},
+		{[]any{1, "a"}, "9201a161"},
+		{map[string]any{"b": 2, "a": 1}, "82a16101a16202"},
+		{jsonTestStruct{ID: 1, Name: "x"}, "82a2696401a46e616d65a178"},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		resultBytes, err := MsgPack_Marshal(testCase.input)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%#v) %v", testCase.input, err)
+		} else if hex.EncodeToString(resultBytes) != testCase.expectedHex {
+			t.Errorf("(%#v) resultHex = %q but should = %q", testCase.input, hex.EncodeToString(resultBytes), testCase.expectedHex)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// MsgPack_Unmarshal
+//------------------------------------------------------------
+
+func TestMsgPack_Unmarshal(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		dataHex        string
+		expectedString string
+	}{
+		{"cfffffffffffffffff", "18446744073709551615"},
+		{"d38000000000000000", "-9223372036854775808"},
+		{"d1ff7f", "-129"},
+		{"cd0100", "256"},
+		{"dc0002c0c3", `[null,true]`},
+		{"de0001a161dd00000001a0", `{"a":[""]}`},
+		{"da0003616263", `"abc"`},
+		{"c50001ff", `"/w=="`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		dataBytes, _ := hex.DecodeString(testCase.dataHex)
+		//--------------------
+		value, err := MsgPack_Unmarshal(dataBytes)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%s) %v", testCase.dataHex, err)
+			continue
+		}
+		//--------------------
+		resultString, _ := JSON_encode(value)
+		//--------------------
+		if resultString != testCase.expectedString {
+			t.Errorf("(%s) resultString = %q but should = %q", testCase.dataHex, resultString, testCase.expectedString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	errorCases := []struct {
+		dataHex        string
+		expectedOffset int64
+	}{
+		{"", 0},
+		{"cd01", 1},
+		{"a36162", 1},
+		{"810101", 1},
+		{"c1", 0},
+		{"d40100", 0},
+		{"a2c328", 1},
+		{"dd7fffffff", 5},
+		{"0101", 1},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range errorCases {
+		//--------------------
+		var decodeError DecodeError
+		//--------------------
+		dataBytes, _ := hex.DecodeString(testCase.dataHex)
+		//--------------------
+		_, err := MsgPack_Unmarshal(dataBytes)
+		//--------------------
+		if !errors.As(err, &decodeError) {
+			t.Errorf("(%s) err = %v but should be a DecodeError", testCase.dataHex, err)
+		} else if decodeError.Offset != testCase.expectedOffset {
+			t.Errorf("(%s) offset = %d but should = %d (%v)", testCase.dataHex, decodeError.Offset, testCase.expectedOffset, err)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
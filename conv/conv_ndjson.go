@@ -0,0 +1,206 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+//------------------------------------------------------------
+
+const NDJSON_MAX_LINE_SIZE = 1024 * 1024
+
+//------------------------------------------------------------
+
+// NDJSONError => error together with the (1 based) line number it occurred on
+type NDJSONError struct {
+	Line int
+	Err  error
+}
+
+func (err NDJSONError) Error() string {
+	return fmt.Sprintf("ndjson line %d: %v", err.Line, err.Err)
+}
+
+func (err NDJSONError) Unwrap() error {
+	return err.Err
+}
+
+//------------------------------------------------------------
+
+type NDJSONWriter struct {
+	encoder *json.Encoder
+}
+
+//------------------------------------------------------------
+
+type NDJSONReader struct {
+	scanner    *bufio.Scanner
+	maxSize    int
+	lineNumber int
+	useNumber  bool
+	err        error
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// NewNDJSONWriter => writes one json value per line without escaping html characters
+//------------------------------------------------------------
+
+func NewNDJSONWriter(writer io.Writer) *NDJSONWriter {
+	//------------------------------------------------------------
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	//------------------------------------------------------------
+	return &NDJSONWriter{encoder: encoder}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NDJSONWriter - Write
+//------------------------------------------------------------
+
+func (ndjsonWriter *NDJSONWriter) Write(value any) error {
+	//------------------------------------------------------------
+	// json.Encoder writes compact json followed by "\n"
+	return ndjsonWriter.encoder.Encode(value)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// NewNDJSONReader => reads one json value per line, blank lines are skipped
+// and lines longer than maxLineSize (default NDJSON_MAX_LINE_SIZE) return an error
+//------------------------------------------------------------
+
+func NewNDJSONReader(reader io.Reader, maxLineSize ...int) *NDJSONReader {
+	//------------------------------------------------------------
+	maxSize := NDJSON_MAX_LINE_SIZE
+	//--------------------
+	if len(maxLineSize) > 0 && maxLineSize[0] > 0 {
+		maxSize = maxLineSize[0]
+	}
+	//------------------------------------------------------------
+	scanner := bufio.NewScanner(reader)
+	// allow for the line terminator which is not part of the line
+	scanner.Buffer(make([]byte, 0, min(maxSize+2, 64*1024)), maxSize+2)
+	//------------------------------------------------------------
+	return &NDJSONReader{scanner: scanner, maxSize: maxSize}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NDJSONReader - UseNumber => numbers decoded into interfaces are kept as json.Number
+//------------------------------------------------------------
+
+func (ndjsonReader *NDJSONReader) UseNumber() {
+	//------------------------------------------------------------
+	ndjsonReader.useNumber = true
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NDJSONReader - Line => line number of the last line read
+//------------------------------------------------------------
+
+func (ndjsonReader *NDJSONReader) Line() int {
+	//------------------------------------------------------------
+	return ndjsonReader.lineNumber
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NDJSONReader - Read => returns the next decoded value or io.EOF
+//------------------------------------------------------------
+
+func (ndjsonReader *NDJSONReader) Read() (any, error) {
+	//------------------------------------------------------------
+	var value any
+	//------------------------------------------------------------
+	err := ndjsonReader.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	return value, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NDJSONReader - Decode => decodes the next line into value or returns io.EOF
+//------------------------------------------------------------
+
+func (ndjsonReader *NDJSONReader) Decode(value any) error {
+	//------------------------------------------------------------
+	if ndjsonReader.err != nil {
+		return ndjsonReader.err
+	}
+	//------------------------------------------------------------
+	for ndjsonReader.scanner.Scan() {
+		//--------------------
+		ndjsonReader.lineNumber++
+		//--------------------
+		// the scanner buffer leaves room for "\r\n" so an LF terminated line can be one byte too long
+		if len(bytes.TrimSuffix(ndjsonReader.scanner.Bytes(), []byte("\r"))) > ndjsonReader.maxSize {
+			ndjsonReader.err = NDJSONError{Line: ndjsonReader.lineNumber, Err: bufio.ErrTooLong}
+			return ndjsonReader.err
+		}
+		//--------------------
+		lineBytes := bytes.TrimSpace(ndjsonReader.scanner.Bytes())
+		if len(lineBytes) == 0 {
+			continue
+		}
+		//--------------------
+		decoder := json.NewDecoder(bytes.NewReader(lineBytes))
+		//--------------------
+		if ndjsonReader.useNumber {
+			decoder.UseNumber()
+		}
+		//--------------------
+		err := decoder.Decode(value)
+		if err == nil {
+			if _, tokenErr := decoder.Token(); tokenErr != io.EOF {
+				err = errors.New("invalid character after top-level value")
+			}
+		}
+		//--------------------
+		if err != nil {
+			// decode errors are not sticky so the caller can skip bad lines
+			return NDJSONError{Line: ndjsonReader.lineNumber, Err: err}
+		}
+		//--------------------
+		return nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if err := ndjsonReader.scanner.Err(); err != nil {
+		ndjsonReader.err = NDJSONError{Line: ndjsonReader.lineNumber + 1, Err: err}
+	} else {
+		ndjsonReader.err = io.EOF
+	}
+	//------------------------------------------------------------
+	return ndjsonReader.err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
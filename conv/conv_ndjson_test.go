@@ -0,0 +1,173 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// NDJSONWriter
+//------------------------------------------------------------
+
+func TestNDJSONWriter(t *testing.T) {
+	//------------------------------------------------------------
+	var buffer bytes.Buffer
+	//------------------------------------------------------------
+	ndjsonWriter := NewNDJSONWriter(&buffer)
+	//------------------------------------------------------------
+	for _, value := range []any{map[string]any{"html": "<&>", "n": 1}, []any{1, "a"}, nil} {
+		if err := ndjsonWriter.Write(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//------------------------------------------------------------
+	expectedString := "{\"html\":\"<&>\",\"n\":1}\n[1,\"a\"]\nnull\n"
+	//--------------------
+	if buffer.String() != expectedString {
+		t.Errorf("resultString = %q but should = %q", buffer.String(), expectedString)
+	}
+	//------------------------------------------------------------
+	if err := ndjsonWriter.Write(func() {}); err == nil {
+		t.Error("unsupported value should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NDJSONReader
+//------------------------------------------------------------
+
+func TestNDJSONReader(t *testing.T) {
+	//------------------------------------------------------------
+	ndjsonReader := NewNDJSONReader(strings.NewReader("{\"a\":1}\r\n\n  \n[1,2]\n{bad}\n\"last\""))
+	//------------------------------------------------------------
+	testCases := []struct {
+		expectedString string
+		expectedLine   int
+	}{
+		{`{"a":1}`, 1},
+		{`[1,2]`, 4},
+		{"", 5},
+		{`"last"`, 6},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		value, err := ndjsonReader.Read()
+		//--------------------
+		if testCase.expectedString == "" {
+			//--------------------
+			var ndjsonError NDJSONError
+			//--------------------
+			if !errors.As(err, &ndjsonError) || ndjsonError.Line != testCase.expectedLine {
+				t.Errorf("err = %v but should be an NDJSONError on line %d", err, testCase.expectedLine)
+			}
+			//--------------------
+			continue
+		}
+		//--------------------
+		resultString, _ := JSON_encode(value)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if resultString != testCase.expectedString {
+			t.Errorf("resultString = %q but should = %q", resultString, testCase.expectedString)
+		} else if ndjsonReader.Line() != testCase.expectedLine {
+			t.Errorf("Line() = %d but should = %d", ndjsonReader.Line(), testCase.expectedLine)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if _, err := ndjsonReader.Read(); err != io.EOF {
+		t.Errorf("err = %v but should = %v", err, io.EOF)
+	}
+	//------------------------------------------------------------
+	if _, err := NewNDJSONReader(strings.NewReader("1 2\n")).Read(); err == nil {
+		t.Error("more than one value on a line should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NDJSONReader - Decode / UseNumber
+//------------------------------------------------------------
+
+func TestNDJSONReader_Decode(t *testing.T) {
+	//------------------------------------------------------------
+	ndjsonReader := NewNDJSONReader(strings.NewReader("{\"id\":9007199254740993}\n"))
+	ndjsonReader.UseNumber()
+	//------------------------------------------------------------
+	var valueMap map[string]any
+	//--------------------
+	if err := ndjsonReader.Decode(&valueMap); err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	if valueMap["id"] != json.Number("9007199254740993") {
+		t.Errorf("valueMap[\"id\"] = %#v but should = %#v", valueMap["id"], json.Number("9007199254740993"))
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NDJSONReader - max line size
+//------------------------------------------------------------
+
+func TestNDJSONReader_maxLineSize(t *testing.T) {
+	//------------------------------------------------------------
+	dataString := "\"" + strings.Repeat("x", 20) + "\"\n"
+	//------------------------------------------------------------
+	ndjsonReader := NewNDJSONReader(strings.NewReader("1\n"+dataString), 16)
+	//--------------------
+	if _, err := ndjsonReader.Read(); err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	var ndjsonError NDJSONError
+	//--------------------
+	_, err := ndjsonReader.Read()
+	//--------------------
+	if !errors.As(err, &ndjsonError) || ndjsonError.Line != 2 || !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("err = %v but should be an NDJSONError on line 2 wrapping %v", err, bufio.ErrTooLong)
+	}
+	//------------------------------------------------------------
+	// errors reading the underlying stream are sticky
+	if _, err = ndjsonReader.Read(); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("err = %v but should wrap %v", err, bufio.ErrTooLong)
+	}
+	//------------------------------------------------------------
+	ndjsonReader = NewNDJSONReader(strings.NewReader(dataString), 22)
+	//--------------------
+	if _, err = ndjsonReader.Read(); err != nil {
+		t.Error(err)
+	}
+	//------------------------------------------------------------
+	// a line of exactly maxLineSize+1 bytes (22) followed by "\n" still fits the scanner buffer
+	ndjsonReader = NewNDJSONReader(strings.NewReader(dataString+"1\n"), 21)
+	//--------------------
+	_, err = ndjsonReader.Read()
+	//--------------------
+	if !errors.As(err, &ndjsonError) || ndjsonError.Line != 1 || !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("err = %v but should be an NDJSONError on line 1 wrapping %v", err, bufio.ErrTooLong)
+	}
+	//--------------------
+	if _, err = ndjsonReader.Read(); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("err = %v but should wrap %v", err, bufio.ErrTooLong)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
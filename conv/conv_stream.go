@@ -0,0 +1,215 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package conv
+
+import (
+	"io"
+)
+
+//------------------------------------------------------------
+
+// chunk sizes kept as multiples of the 4 byte => 5 character grouping
+const baseStreamEncodeChunk = 4 * 1024
+const baseStreamDecodeChunk = 5 * 1024
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+type baseEncoder struct {
+	//--------------------
+	writer io.Writer
+	err    error
+	//--------------------
+	buffer       [4]byte
+	bufferLength int
+	//--------------------
+	output []byte
+	//--------------------
+}
+
+//------------------------------------------------------------
+// NewBaseEncoder => returns a writer that Base encodes data written to it
+//------------------------------------------------------------
+// Close must be called to flush any partially written 4 byte group
+//------------------------------------------------------------
+
+func NewBaseEncoder(writer io.Writer) io.WriteCloser {
+	//------------------------------------------------------------
+	return &baseEncoder{writer: writer}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// baseEncoder - Write
+//------------------------------------------------------------
+
+func (encoder *baseEncoder) Write(dataBytes []byte) (int, error) {
+	//------------------------------------------------------------
+	if encoder.err != nil {
+		return 0, encoder.err
+	}
+	//------------------------------------------------------------
+	dataLength := len(dataBytes)
+	//------------------------------------------------------------
+	// complete any partial group left over from the previous write
+	if encoder.bufferLength > 0 {
+		//--------------------
+		copied := copy(encoder.buffer[encoder.bufferLength:], dataBytes)
+		encoder.bufferLength += copied
+		dataBytes = dataBytes[copied:]
+		//--------------------
+		if encoder.bufferLength < 4 {
+			return dataLength, nil
+		}
+		//--------------------
+		if encoder.err = encoder.write(encoder.buffer[:]); encoder.err != nil {
+			return 0, encoder.err
+		}
+		//--------------------
+		encoder.bufferLength = 0
+		//--------------------
+	}
+	//------------------------------------------------------------
+	for len(dataBytes) >= 4 {
+		//--------------------
+		chunkLength := min(len(dataBytes)/4*4, baseStreamEncodeChunk)
+		//--------------------
+		if encoder.err = encoder.write(dataBytes[:chunkLength]); encoder.err != nil {
+			return dataLength - len(dataBytes), encoder.err
+		}
+		//--------------------
+		dataBytes = dataBytes[chunkLength:]
+		//--------------------
+	}
+	//------------------------------------------------------------
+	encoder.bufferLength = copy(encoder.buffer[:], dataBytes)
+	//------------------------------------------------------------
+	return dataLength, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// baseEncoder - Close
+//------------------------------------------------------------
+
+func (encoder *baseEncoder) Close() error {
+	//------------------------------------------------------------
+	if encoder.err == nil && encoder.bufferLength > 0 {
+		//--------------------
+		encoder.err = encoder.write(encoder.buffer[:encoder.bufferLength])
+		encoder.bufferLength = 0
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return encoder.err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// baseEncoder - write
+//------------------------------------------------------------
+
+func (encoder *baseEncoder) write(dataBytes []byte) error {
+	//------------------------------------------------------------
+	encoder.output = AppendBase(encoder.output[:0], dataBytes)
+	//------------------------------------------------------------
+	_, err := encoder.writer.Write(encoder.output)
+	//------------------------------------------------------------
+	return err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+type baseDecoder struct {
+	//--------------------
+	reader io.Reader
+	err    error
+	//--------------------
+	buffer       [baseStreamDecodeChunk]byte
+	bufferLength int
+	//--------------------
+	output       []byte
+	outputBuffer []byte
+	//--------------------
+}
+
+//------------------------------------------------------------
+// NewBaseDecoder => returns a reader that Base decodes data read from reader
+//------------------------------------------------------------
+
+func NewBaseDecoder(reader io.Reader) io.Reader {
+	//------------------------------------------------------------
+	return &baseDecoder{reader: reader}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// baseDecoder - Read
+//------------------------------------------------------------
+
+func (decoder *baseDecoder) Read(dataBytes []byte) (int, error) {
+	//------------------------------------------------------------
+	if len(dataBytes) == 0 {
+		return 0, nil
+	}
+	//------------------------------------------------------------
+	for {
+		//------------------------------------------------------------
+		if len(decoder.output) > 0 {
+			//--------------------
+			copied := copy(dataBytes, decoder.output)
+			decoder.output = decoder.output[copied:]
+			//--------------------
+			return copied, nil
+			//--------------------
+		}
+		//------------------------------------------------------------
+		if decoder.err != nil {
+			return 0, decoder.err
+		}
+		//------------------------------------------------------------
+		bytesRead, readErr := decoder.reader.Read(decoder.buffer[decoder.bufferLength:])
+		decoder.bufferLength += bytesRead
+		//------------------------------------------------------------
+		// only decode whole 5 character groups until the end of the input is reached
+		decodeLength := decoder.bufferLength / 5 * 5
+		if readErr != nil {
+			decodeLength = decoder.bufferLength
+		}
+		//------------------------------------------------------------
+		if decodeLength > 0 {
+			//--------------------
+			outputBuffer, err := AppendBase_decode(decoder.outputBuffer[:0], decoder.buffer[:decodeLength])
+			if err != nil {
+				decoder.err = err
+				return 0, err
+			}
+			//--------------------
+			decoder.outputBuffer = outputBuffer
+			decoder.output = outputBuffer
+			decoder.bufferLength = copy(decoder.buffer[:], decoder.buffer[decodeLength:decoder.bufferLength])
+			//--------------------
+		}
+		//------------------------------------------------------------
+		if readErr != nil {
+			decoder.err = readErr
+		}
+		//------------------------------------------------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
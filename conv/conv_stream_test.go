@@ -0,0 +1,113 @@
+//------------------------------------------------------------
+
+package conv
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// NewBaseEncoder
+//------------------------------------------------------------
+
+func TestNewBaseEncoder(t *testing.T) {
+	//--------------------------------------------------
+	testCases := []string{
+		"",
+		"A",
+		"AA",
+		"AAA",
+		"AAAA",
+		"\x00\x00\x00\x00",
+		"ABC\U0001f427",
+		strings.Repeat("May your trails be crooked, winding, lonesome, dangerous. ", 200),
+	}
+	//--------------------------------------------------
+	for _, dataString := range testCases {
+		//--------------------
+		expectedString := Base_encode(dataString)
+		//--------------------
+		for _, writeSize := range []int{1, 3, 7, 4096} {
+			//--------------------
+			var outputBuffer bytes.Buffer
+			encoder := NewBaseEncoder(&outputBuffer)
+			//--------------------
+			for index := 0; index < len(dataString); index += writeSize {
+				_, err := encoder.Write([]byte(dataString[index:min(index+writeSize, len(dataString))]))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			//--------------------
+			if err := encoder.Close(); err != nil {
+				t.Fatal(err)
+			}
+			//--------------------
+			if outputBuffer.String() != expectedString {
+				t.Errorf("(%q, %d) resultString = %q but should = %q", dataString, writeSize, outputBuffer.String(), expectedString)
+			}
+			//--------------------
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NewBaseDecoder
+//------------------------------------------------------------
+
+func TestNewBaseDecoder(t *testing.T) {
+	//--------------------------------------------------
+	testCases := []string{
+		"",
+		"8q",
+		"8x]",
+		"8x_i",
+		"8x_j)",
+		"!!!!!",
+		"8xix1W</w",
+		Base_encode(strings.Repeat("May your trails be crooked, winding, lonesome, dangerous. ", 200)),
+	}
+	//--------------------------------------------------
+	for _, dataString := range testCases {
+		//--------------------
+		expectedString, _ := Base_decode(dataString)
+		//--------------------
+		for _, reader := range []io.Reader{
+			strings.NewReader(dataString),
+			iotest.OneByteReader(strings.NewReader(dataString)),
+			iotest.HalfReader(strings.NewReader(dataString)),
+		} {
+			//--------------------
+			resultBytes, err := io.ReadAll(NewBaseDecoder(reader))
+			//--------------------
+			if err != nil {
+				t.Error(err)
+			} else if string(resultBytes) != expectedString {
+				t.Errorf("(%q) resultString = %q but should = %q", dataString, string(resultBytes), expectedString)
+			}
+			//--------------------
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+	_, err := io.ReadAll(NewBaseDecoder(strings.NewReader("8x_j)8x\"")))
+	//--------------------
+	if err == nil {
+		t.Error("invalid characters should return an error")
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
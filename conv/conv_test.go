@@ -4,6 +4,7 @@ package conv
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -71,6 +72,131 @@ func TestBase_decode(t *testing.T) {
 	//--------------------------------------------------
 }
 
+//------------------------------------------------------------
+// AppendBase
+//------------------------------------------------------------
+
+func TestAppendBase(t *testing.T) {
+	//--------------------------------------------------
+	testCases := []string{"", "A", "AA", "AAA", "AAAA", "\x00\x00\x00\x00", "ABC\U0001f427"}
+	//--------------------------------------------------
+	for _, dataString := range testCases {
+		//--------------------
+		expectedString := "prefix:" + Base_encode(dataString)
+		//--------------------
+		resultBytes := AppendBase([]byte("prefix:"), []byte(dataString))
+		//--------------------
+		if string(resultBytes) != expectedString {
+			t.Errorf("(%q) resultString = %q but should = %q", dataString, string(resultBytes), expectedString)
+		}
+		//--------------------
+		resultBytes = Base_encode_bytes([]byte(dataString))
+		//--------------------
+		if resultBytes == nil || string(resultBytes) != Base_encode(dataString) {
+			t.Errorf("(%q) Base_encode_bytes = %#v but should = %q", dataString, resultBytes, Base_encode(dataString))
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase_decode
+//------------------------------------------------------------
+
+func TestAppendBase_decode(t *testing.T) {
+	//--------------------------------------------------
+	testCases := []string{"", "8q", "8x]", "8x_i", "8x_j)", "!!!!!", "8xix1W</w"}
+	//--------------------------------------------------
+	for _, dataString := range testCases {
+		//--------------------
+		decodedString, _ := Base_decode(dataString)
+		expectedString := "prefix:" + decodedString
+		//--------------------
+		resultBytes, err := AppendBase_decode([]byte("prefix:"), []byte(dataString))
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if string(resultBytes) != expectedString {
+			t.Errorf("(%q) resultString = %q but should = %q", dataString, string(resultBytes), expectedString)
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+	resultBytes, err := AppendBase_decode([]byte("prefix:"), []byte("8x_j)8x\""))
+	//--------------------
+	if err == nil {
+		t.Error("invalid characters should return an error")
+	} else if string(resultBytes) != "prefix:" {
+		t.Errorf("resultString = %q but should = %q", string(resultBytes), "prefix:")
+	}
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NewBaseEncoding
+//------------------------------------------------------------
+
+func TestNewBaseEncoding(t *testing.T) {
+	//--------------------------------------------------
+	// same characters as BASE_CHARSET but with "<>&;" moved to the end
+	alphabet := "!#%()*+,-./0123456789:=?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[]^_abcdefghijklmnopqrstuvwxyz<>&;"
+	//--------------------------------------------------
+	encoding, err := NewBaseEncoding(alphabet)
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	if encoding.Alphabet() != alphabet {
+		t.Errorf("Alphabet() = %q but should = %q", encoding.Alphabet(), alphabet)
+	}
+	//--------------------------------------------------
+	for _, dataString := range []string{"", "A", "ABC", "\x00\x00\x00\x00", "ABC <> &quot; £ 日本語\U0001f427"} {
+		//--------------------
+		defaultString := Base_encode(dataString)
+		//--------------------
+		expectedBytes := []byte(defaultString)
+		for index := range expectedBytes {
+			expectedBytes[index] = alphabet[strings.IndexByte(BASE_CHARSET, expectedBytes[index])]
+		}
+		//--------------------
+		resultBytes := encoding.Encode([]byte(dataString))
+		//--------------------
+		if string(resultBytes) != string(expectedBytes) {
+			t.Errorf("(%q) resultString = %q but should = %q", dataString, string(resultBytes), string(expectedBytes))
+		}
+		//--------------------
+		decodedBytes, err := encoding.Decode(resultBytes)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if string(decodedBytes) != dataString {
+			t.Errorf("(%q) decodedString = %q but should = %q", dataString, string(decodedBytes), dataString)
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+	if _, err = encoding.Decode([]byte("8x$j)")); err == nil {
+		t.Error("invalid characters should return an error")
+	}
+	//--------------------------------------------------
+	testCases := []string{
+		"",
+		BASE_CHARSET[:84],
+		BASE_CHARSET[:84] + "!",
+		BASE_CHARSET[:84] + " ",
+		BASE_CHARSET[:83] + "£",
+	}
+	//--------------------------------------------------
+	for _, testCase := range testCases {
+		if _, err := NewBaseEncoding(testCase); err == nil {
+			t.Errorf("(%q) invalid alphabet should return an error", testCase)
+		}
+	}
+	//--------------------------------------------------
+}
+
 //------------------------------------------------------------
 // Base64_encode
 //------------------------------------------------------------
@@ -259,6 +385,74 @@ func TestBase64url_Base64(t *testing.T) {
 	//------------------------------------------------------------
 }
 
+//------------------------------------------------------------
+// AppendBase64
+//------------------------------------------------------------
+
+func TestAppendBase64(t *testing.T) {
+	//------------------------------------------------------------
+	dataString := "ABC <> &quot; \u00A3 \u65E5\u672C\u8A9E\U0001f427"
+	base64String := "QUJDIDw+ICZxdW90OyDCoyDml6XmnKzoqp7wn5Cn"
+	//------------------------------------------------------------
+	resultBytes := AppendBase64([]byte("prefix:"), []byte(dataString))
+	//--------------------------------------------------
+	if string(resultBytes) != "prefix:"+base64String {
+		t.Errorf("resultString = %q but should = %q", string(resultBytes), "prefix:"+base64String)
+	}
+	//------------------------------------------------------------
+	resultBytes, err := AppendBase64_decode([]byte("prefix:"), []byte(base64String))
+	//--------------------------------------------------
+	if err != nil {
+		t.Error(err)
+	} else if string(resultBytes) != "prefix:"+dataString {
+		t.Errorf("resultString = %q but should = %q", string(resultBytes), "prefix:"+dataString)
+	}
+	//------------------------------------------------------------
+	resultBytes, err = AppendBase64_decode([]byte("prefix:"), []byte("QUJ*"))
+	//--------------------------------------------------
+	if err == nil {
+		t.Error("invalid characters should return an error")
+	} else if string(resultBytes) != "prefix:" {
+		t.Errorf("resultString = %q but should = %q", string(resultBytes), "prefix:")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// AppendBase64url
+//------------------------------------------------------------
+
+func TestAppendBase64url(t *testing.T) {
+	//------------------------------------------------------------
+	dataString := "\u65E5\u672C\u8A9E\U0001f427"
+	base64urlString := "5pel5pys6Kqe8J-Qpw"
+	//------------------------------------------------------------
+	resultBytes := AppendBase64url([]byte("prefix:"), []byte(dataString))
+	//--------------------------------------------------
+	if string(resultBytes) != "prefix:"+base64urlString {
+		t.Errorf("resultString = %q but should = %q", string(resultBytes), "prefix:"+base64urlString)
+	}
+	//------------------------------------------------------------
+	for _, encodedString := range []string{base64urlString, base64urlString + "==", "5pel5pys6Kqe8J+Qpw"} {
+		//--------------------
+		resultBytes, err := AppendBase64url_decode([]byte("prefix:"), []byte(encodedString))
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if string(resultBytes) != "prefix:"+dataString {
+			t.Errorf("(%q) resultString = %q but should = %q", encodedString, string(resultBytes), "prefix:"+dataString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	resultBytes = Base64url_encode_bytes([]byte(dataString))
+	//--------------------------------------------------
+	if string(resultBytes) != base64urlString {
+		t.Errorf("resultString = %q but should = %q", string(resultBytes), base64urlString)
+	}
+	//------------------------------------------------------------
+}
+
 //------------------------------------------------------------
 //############################################################
 //------------------------------------------------------------
@@ -402,6 +596,44 @@ func TestBase91_decode(t *testing.T) {
 	//--------------------------------------------------
 }
 
+//------------------------------------------------------------
+// AppendBase91
+//------------------------------------------------------------
+
+func TestAppendBase91(t *testing.T) {
+	//--------------------------------------------------
+	dataString := "May your trails be crooked, winding, lonesome, dangerous, leading to the most amazing view. May your mountains rise into and above the clouds."
+	//--------------------------------------------------
+	for _, escapeBool := range []bool{false, true} {
+		//--------------------
+		expectedString := "prefix:" + Base91_encode(dataString, escapeBool)
+		//--------------------
+		resultBytes := AppendBase91([]byte("prefix:"), []byte(dataString), escapeBool)
+		//--------------------
+		if string(resultBytes) != expectedString {
+			t.Errorf("(%v) resultString = %q but should = %q", escapeBool, string(resultBytes), expectedString)
+		}
+		//--------------------
+		resultBytes, err := AppendBase91_decode([]byte("prefix:"), resultBytes[len("prefix:"):], escapeBool)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if string(resultBytes) != "prefix:"+dataString {
+			t.Errorf("(%v) resultString = %q but should = %q", escapeBool, string(resultBytes), "prefix:"+dataString)
+		}
+		//--------------------
+	}
+	//--------------------------------------------------
+	resultBytes, err := AppendBase91_decode([]byte("prefix:"), []byte("8D9K -x"), true)
+	//--------------------
+	if err == nil {
+		t.Error("invalid characters should return an error")
+	} else if string(resultBytes) != "prefix:" {
+		t.Errorf("resultString = %q but should = %q", string(resultBytes), "prefix:")
+	}
+	//--------------------------------------------------
+}
+
 //------------------------------------------------------------
 // ############################################################
 //------------------------------------------------------------
@@ -67,8 +67,13 @@ func (conn *MySQLdb) Connect(checkENV ...bool) error {
 		//--------------------
 		conn.Host = os.Getenv("MYSQL_HOST")
 		//--------------------
-		conn.User = os.Getenv("MYSQL_USER")
-		conn.Password = os.Getenv("MYSQL_PWD")
+		// MYSQL_USER_FILE and MYSQL_PWD_FILE may name Docker secrets files
+		if conn.User, err = system.GetENVOrFile("MYSQL_USER"); err != nil {
+			return err
+		}
+		if conn.Password, err = system.GetENVOrFile("MYSQL_PWD"); err != nil {
+			return err
+		}
 		//--------------------
 		conn.AllowNativePasswords = os.Getenv("MYSQL_ALLOW_NATIVE_PASSWORDS") == "true"
 		//--------------------
@@ -541,6 +546,13 @@ func (conn *MySQLdb) ScanRows(sqlRows *sql.Rows) ([]map[string]any, error) {
 					value = system.ToBytes(value)
 				case "BOOL", "BOOLEAN":
 					value = system.ToBool(value)
+				case "DATE", "DATETIME", "TIMESTAMP":
+					// NULL and unparseable values (such as 0000-00-00 00:00:00) are kept as they are
+					if value != nil {
+						if timeValue, err := system.ToTimeE(value); err == nil {
+							value = timeValue
+						}
+					}
 				}
 				//--------------------
 				record[Name] = value
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	_ "github.com/mattn/go-sqlite3"
 )
 
 //------------------------------------------------------------
@@ -579,6 +581,58 @@ func TestTableExists(t *testing.T) {
 	//------------------------------------------------------------
 }
 
+//--------------------------------------------------------------------------------
+// ScanRows NULL and invalid dates
+//--------------------------------------------------------------------------------
+
+func TestScanRowsDates(t *testing.T) {
+	//------------------------------------------------------------
+	// ScanRows only needs the column type names so sqlite stands in for the server
+	// (the invalid date is stored as a blob so the sqlite driver returns its raw bytes)
+	DB, err := sql.Open("sqlite3", ":memory:")
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	defer DB.Close()
+	//------------------------------------------------------------
+	_, err = DB.Exec("CREATE TABLE test_dates (id INTEGER, created DATE, updated TIMESTAMP); INSERT INTO test_dates VALUES (1, '2024-01-02', CAST('0000-00-00 00:00:00' AS BLOB)), (2, NULL, NULL);")
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	rows, err := DB.Query("SELECT id, created, updated FROM test_dates ORDER BY id;")
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	defer rows.Close()
+	//------------------------------------------------------------
+	records, err := (&MySQLdb{}).ScanRows(rows)
+	//--------------------
+	if err != nil || len(records) != 2 {
+		t.Fatalf("records = %v, err = %v", records, err)
+	}
+	//------------------------------------------------------------
+	expectedTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	//--------------------
+	if created, ok := records[0]["created"].(time.Time); !ok || !created.Equal(expectedTime) {
+		t.Errorf("created = %#v but should = %v", records[0]["created"], expectedTime)
+	}
+	//--------------------
+	if records[0]["updated"] != "0000-00-00 00:00:00" {
+		t.Errorf("updated = %#v but should = %q", records[0]["updated"], "0000-00-00 00:00:00")
+	}
+	//--------------------
+	if records[1]["created"] != nil || records[1]["updated"] != nil {
+		t.Errorf("created = %#v, updated = %#v but should both = nil", records[1]["created"], records[1]["updated"])
+	}
+	//------------------------------------------------------------
+}
+
 //--------------------------------------------------------------------------------
 // CheckTableName
 //--------------------------------------------------------------------------------
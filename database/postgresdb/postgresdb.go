@@ -66,8 +66,13 @@ func (conn *PostgresDBStruct) Connect(checkENV ...bool) error {
 		//--------------------
 		conn.Host = os.Getenv("POSTGRES_HOST")
 		//--------------------
-		conn.User = os.Getenv("POSTGRES_USER")
-		conn.Password = os.Getenv("POSTGRES_PWD")
+		// POSTGRES_USER_FILE and POSTGRES_PWD_FILE may name Docker secrets files
+		if conn.User, err = system.GetENVOrFile("POSTGRES_USER"); err != nil {
+			return err
+		}
+		if conn.Password, err = system.GetENVOrFile("POSTGRES_PWD"); err != nil {
+			return err
+		}
 		//--------------------
 		if conn.Database == "" {
 			conn.Database = os.Getenv("POSTGRES_DATABASE")
@@ -472,6 +477,13 @@ func (conn *PostgresDBStruct) ScanRows(sqlRows *sql.Rows) ([]map[string]any, err
 					value = system.ToBytes(value)
 				case "BOOL", "BOOLEAN":
 					value = system.ToBool(value)
+				case "DATE", "TIMESTAMP", "TIMESTAMPTZ":
+					// NULL and unparseable values (such as 0000-00-00 00:00:00) are kept as they are
+					if value != nil {
+						if timeValue, err := system.ToTimeE(value); err == nil {
+							value = timeValue
+						}
+					}
 				}
 				//--------------------
 				record[Name] = value
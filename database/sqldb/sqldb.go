@@ -91,8 +91,13 @@ func (conn *SQLdb) Connect(checkENV ...bool) error {
 			//------------------------------------------------------------
 			conn.Host = os.Getenv("MYSQL_HOST")
 			//--------------------
-			conn.User = os.Getenv("MYSQL_USER")
-			conn.Password = os.Getenv("MYSQL_PWD")
+			// MYSQL_USER_FILE and MYSQL_PWD_FILE may name Docker secrets files
+			if conn.User, err = system.GetENVOrFile("MYSQL_USER"); err != nil {
+				return err
+			}
+			if conn.Password, err = system.GetENVOrFile("MYSQL_PWD"); err != nil {
+				return err
+			}
 			//--------------------
 			conn.AllowNativePasswords = os.Getenv("MYSQL_ALLOW_NATIVE_PASSWORDS") == "true"
 			//--------------------
@@ -124,8 +129,13 @@ func (conn *SQLdb) Connect(checkENV ...bool) error {
 			//------------------------------------------------------------
 			conn.Host = os.Getenv("POSTGRES_HOST")
 			//--------------------
-			conn.User = os.Getenv("POSTGRES_USER")
-			conn.Password = os.Getenv("POSTGRES_PWD")
+			// POSTGRES_USER_FILE and POSTGRES_PWD_FILE may name Docker secrets files
+			if conn.User, err = system.GetENVOrFile("POSTGRES_USER"); err != nil {
+				return err
+			}
+			if conn.Password, err = system.GetENVOrFile("POSTGRES_PWD"); err != nil {
+				return err
+			}
 			//--------------------
 			if conn.Database == "" {
 				conn.Database = os.Getenv("POSTGRES_DATABASE")
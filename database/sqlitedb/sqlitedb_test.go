@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/timbrockley/golang-main/file"
+	"github.com/timbrockley/golang-main/system"
 )
 
 //--------------------------------------------------------------------------------
@@ -689,6 +690,64 @@ func TestMemoryDatabase2(t *testing.T) {
 	//------------------------------------------------------------
 }
 
+//--------------------------------------------------------------------------------
+// system.UUID round trip
+//--------------------------------------------------------------------------------
+
+func TestUUIDRecords(t *testing.T) {
+	//------------------------------------------------------------
+	conn, err := Connect(SQLiteDB{Database: ":memory:", AutoCreate: false})
+	//--------------------------------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------------------------------
+	defer conn.Close()
+	//------------------------------------------------------------
+	_, err = conn.Exec("CREATE TABLE users(id CHAR(36) PRIMARY KEY, name VARCHAR(255))")
+	//--------------------------------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	uuid, err := system.NewUUIDv7()
+	//--------------------------------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------------------------------
+	_, err = conn.Exec("INSERT INTO users(id, name) VALUES(?, ?)", uuid, "Tim")
+	//--------------------------------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	records, err := conn.QueryRecords("SELECT id FROM users WHERE id = ?", uuid)
+	//--------------------------------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------------------------------
+	if len(records) != 1 {
+		t.Fatalf("len(records) = %d but should = %d", len(records), 1)
+	}
+	//------------------------------------------------------------
+	var resultUUID system.UUID
+	//--------------------------------------------
+	if err = resultUUID.Scan(records[0]["id"]); err != nil {
+		t.Error(err)
+	} else if resultUUID != uuid {
+		t.Errorf("uuid = %s but should = %s", resultUUID, uuid)
+	}
+	//------------------------------------------------------------
+	if err = conn.QueryRow("SELECT id FROM users").Scan(&resultUUID); err != nil {
+		t.Error(err)
+	} else if resultUUID != uuid {
+		t.Errorf("uuid = %s but should = %s", resultUUID, uuid)
+	}
+	//------------------------------------------------------------
+}
+
 //--------------------------------------------------------------------------------
 //################################################################################
 //--------------------------------------------------------------------------------
@@ -10,6 +10,7 @@ require (
 	github.com/mattn/go-runewidth v0.0.16
 	github.com/mattn/go-sqlite3 v1.14.17
 	github.com/mtraver/base91 v1.0.0
+	golang.org/x/crypto v0.24.0
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d
 	golang.org/x/term v0.21.0
 	gopkg.in/yaml.v3 v3.0.1
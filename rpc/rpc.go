@@ -14,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"regexp"
 	"strings"
@@ -29,6 +30,10 @@ const ContentTypeText string = "text/plain; charset=UTF-8"
 
 const ContentTypeJSON string = "application/json; charset=UTF-8"
 
+const ContentTypeCBOR string = "application/cbor"
+
+const ContentTypeMsgPack string = "application/msgpack"
+
 //--------------------------------------------------------------------------------
 
 type RPCStruct struct {
@@ -47,6 +52,9 @@ type RPCStruct struct {
 	//--------------------
 	Encoding string
 	//--------------------
+	// payload format used by the json methods: "" (json), "json", "cbor" or "msgpack"
+	Format string
+	//--------------------
 }
 
 //--------------------------------------------------------------------------------
@@ -83,13 +91,7 @@ func (rpcObject *RPCStruct) RPC_read_request() error {
 		//--------------------------------------------------
 		rpcObject.RequestURL = rpcObject.HttpRequest.URL.String()
 		//--------------------------------------------------
-		requestString = string(requestBytes)
-		//--------------------
-		if rpcObject.Encoding == "base64" {
-			requestString, err = conv.Base64_decode(requestString)
-		} else if rpcObject.Encoding == "base64url" {
-			requestString, err = conv.Base64url_decode(requestString)
-		}
+		requestString, err = rpcObject.decodeString(string(requestBytes))
 		//--------------------
 		if err == nil {
 
@@ -137,10 +139,9 @@ func (rpcObject *RPCStruct) RPC_send_request(requestString string) (string, erro
 	//--------------------------------------------------
 	responseString := ""
 	//--------------------------------------------------
-	if rpcObject.Encoding == "base64" {
-		requestString = conv.Base64_encode(requestString)
-	} else if rpcObject.Encoding == "base64url" {
-		requestString = conv.Base64url_encode(requestString)
+	requestString, err = rpcObject.encodeString(requestString)
+	if err != nil {
+		return responseString, err
 	}
 	//--------------------
 	httpRequest, err = http.NewRequest("POST", rpcObject.ResponseURL, bytes.NewBuffer([]byte(requestString)))
@@ -169,13 +170,7 @@ func (rpcObject *RPCStruct) RPC_send_request(requestString string) (string, erro
 			//--------------------
 			if err == nil {
 				//--------------------
-				responseString = string(responseBytes)
-				//--------------------
-				if rpcObject.Encoding == "base64" {
-					responseString, err = conv.Base64_decode(responseString)
-				} else if rpcObject.Encoding == "base64url" {
-					responseString, err = conv.Base64url_decode(responseString)
-				}
+				responseString, err = rpcObject.decodeString(string(responseBytes))
 				//--------------------
 			}
 			//--------------------
@@ -202,21 +197,21 @@ func (rpcObject *RPCStruct) RPC_send_json_request(requestMap map[string]any) (an
 	var responseString string
 	var responseMap any
 	//--------------------------------------------------
-	requestString, err = RPC_encode_json(requestMap)
+	requestString, err = rpcObject.marshalMap(requestMap)
 	//--------------------
 	if err == nil {
 
 		//--------------------
 		if rpcObject.ResponseHeadersMap["Content-Type"] == "" {
 
-			rpcObject.ResponseHeadersMap["Content-Type"] = ContentTypeJSON
+			rpcObject.ResponseHeadersMap["Content-Type"] = rpcObject.contentType()
 		}
 		//--------------------
 		responseString, err = rpcObject.RPC_send_request(requestString)
 		//--------------------
 		if err == nil {
 
-			responseMap, err = RPC_decode_json(responseString)
+			responseMap, err = rpcObject.unmarshalMap(responseString)
 		}
 		//--------------------
 	}
@@ -240,16 +235,16 @@ func (rpcObject *RPCStruct) RPC_read_json_request() error {
 	if err == nil {
 
 		//--------------------
-		// match what looks like a single json request
+		// match what looks like a single json request (binary formats are not checked)
 		match, _ := regexp.MatchString(`^\s*{.*}\s*$`, rpcObject.RequestString)
-		if !match {
+		if !match && rpcObject.isJSONFormat() {
 
 			err = errors.New("invalid request")
 
 		} else {
 
 			//--------------------
-			jsonInterface, err = RPC_decode_json(rpcObject.RequestString)
+			jsonInterface, err = rpcObject.unmarshalMap(rpcObject.RequestString)
 			//--------------------
 			if err == nil {
 
@@ -286,7 +281,7 @@ func (rpcObject *RPCStruct) RPC_read_jsonrpc_request() error {
 	if err == nil {
 
 		//--------------------
-		jsonInterface, err = RPC_decode_json(rpcObject.RequestString)
+		jsonInterface, err = rpcObject.unmarshalMap(rpcObject.RequestString)
 		//--------------------
 		if err == nil {
 
@@ -338,21 +333,21 @@ func (rpcObject *RPCStruct) RPC_send_jsonrpc_request(requestMap map[string]any)
 		requestMap["id"] = auto.ID()
 	}
 	//--------------------------------------------------
-	requestString, err = RPC_encode_json(requestMap)
+	requestString, err = rpcObject.marshalMap(requestMap)
 	//--------------------
 	if err == nil {
 
 		//--------------------
 		if rpcObject.ResponseHeadersMap["Content-Type"] == "" {
 
-			rpcObject.ResponseHeadersMap["Content-Type"] = ContentTypeJSON
+			rpcObject.ResponseHeadersMap["Content-Type"] = rpcObject.contentType()
 		}
 		//--------------------
 		responseString, err = rpcObject.RPC_send_request(requestString)
 		//--------------------
 		if err == nil {
 
-			responseMap, err = RPC_decode_json(responseString)
+			responseMap, err = rpcObject.unmarshalMap(responseString)
 		}
 		//--------------------
 	}
@@ -378,13 +373,13 @@ func (rpcObject *RPCStruct) RPC_send_response(responseString string) {
 		rpcObject.ResponseWriter.Header().Set(headerKey, headerValue)
 	}
 	//--------------------
-	if rpcObject.Encoding == "base64" {
-		responseString = conv.Base64_encode(responseString)
-	} else if rpcObject.Encoding == "base64url" {
-		responseString = conv.Base64url_encode(responseString)
+	encodedString, err := rpcObject.encodeString(responseString)
+	if err != nil {
+		// cannot be encoded so send the error unencoded
+		encodedString = errorJSON(err)
 	}
 	//--------------------
-	fmt.Fprint(rpcObject.ResponseWriter, responseString)
+	fmt.Fprint(rpcObject.ResponseWriter, encodedString)
 	//--------------------------------------------------
 }
 
@@ -401,14 +396,14 @@ func (rpcObject *RPCStruct) RPC_send_json_response(responseMap map[string]any) {
 	//--------------------------------------------------
 	if rpcObject.ResponseHeadersMap["Content-Type"] == "" {
 
-		rpcObject.ResponseHeadersMap["Content-Type"] = ContentTypeJSON
+		rpcObject.ResponseHeadersMap["Content-Type"] = rpcObject.contentType()
 	}
 	//--------------------------------------------------
-	responseString, err = RPC_encode_json(responseMap)
+	responseString, err = rpcObject.marshalMap(responseMap)
 	if err != nil {
 
 		//--------------------------------------------------
-		rpcObject.RPC_send_response(fmt.Sprintf(`{"error":%q}`, err))
+		rpcObject.RPC_send_response(errorJSON(err))
 		//--------------------------------------------------
 
 	} else {
@@ -465,7 +460,7 @@ func (rpcObject *RPCStruct) RPC_send_jsonrpc_result(result any) error {
 	//--------------------
 	responseMap["result"] = result
 	//--------------------------------------------------
-	responseString, err := RPC_encode_json(responseMap)
+	responseString, err := rpcObject.marshalMap(responseMap)
 	//--------------------------------------------------
 	if err == nil {
 		rpcObject.RPC_send_response(responseString)
@@ -494,7 +489,7 @@ func (rpcObject *RPCStruct) RPC_send_jsonrpc_error(error any) error {
 	//--------------------
 	responseMap["error"] = error
 	//--------------------------------------------------
-	responseString, err := RPC_encode_json(responseMap)
+	responseString, err := rpcObject.marshalMap(responseMap)
 	//--------------------------------------------------
 	if err == nil {
 		rpcObject.RPC_send_response(responseString)
@@ -714,7 +709,9 @@ func RPC_Handler(responseWriter http.ResponseWriter, httpRequest *http.Request)
 	//--------------------
 	rpcObject.ResponseURL = rpcObject.HttpRequest.URL.String()
 	//--------------------
-	rpcObject.ResponseHeadersMap = map[string]string{"Content-Type": ContentTypeJSON}
+	rpcObject.Format = FormatFromContentType(httpRequest.Header.Get("Content-Type"))
+	//--------------------
+	rpcObject.ResponseHeadersMap = map[string]string{"Content-Type": rpcObject.contentType()}
 	//--------------------
 	err = rpcObject.RPC_read_json_request()
 	//--------------------------------------------------
@@ -773,6 +770,8 @@ func JSONRPC_Handler(responseWriter http.ResponseWriter, httpRequest *http.Reque
 		contentType = ContentTypeJSON
 	}
 	//--------------------
+	rpcObject.Format = FormatFromContentType(contentType)
+	//--------------------
 	rpcObject.ResponseHeadersMap = map[string]string{"Content-Type": contentType}
 	//--------------------
 	err = rpcObject.RPC_read_jsonrpc_request()
@@ -821,6 +820,153 @@ func JSONRPC_Handler(responseWriter http.ResponseWriter, httpRequest *http.Reque
 //################################################################################
 //--------------------------------------------------------------------------------
 
+//--------------------------------------------------------------------------------
+// encodeString => encodes data using the conv codec named by Encoding (if any)
+//--------------------------------------------------------------------------------
+
+func (rpcObject *RPCStruct) encodeString(dataString string) (string, error) {
+	//--------------------------------------------------
+	if rpcObject.Encoding == "" {
+		return dataString, nil
+	}
+	//--------------------------------------------------
+	codec, exists := conv.LookupCodec(rpcObject.Encoding)
+	if !exists {
+		return "", fmt.Errorf("unknown encoding: %s", rpcObject.Encoding)
+	}
+	//--------------------------------------------------
+	return string(codec.Encode([]byte(dataString))), nil
+	//--------------------------------------------------
+}
+
+//--------------------------------------------------------------------------------
+// decodeString => decodes data using the conv codec named by Encoding (if any)
+//--------------------------------------------------------------------------------
+
+func (rpcObject *RPCStruct) decodeString(dataString string) (string, error) {
+	//--------------------------------------------------
+	if rpcObject.Encoding == "" {
+		return dataString, nil
+	}
+	//--------------------------------------------------
+	codec, exists := conv.LookupCodec(rpcObject.Encoding)
+	if !exists {
+		return "", fmt.Errorf("unknown encoding: %s", rpcObject.Encoding)
+	}
+	//--------------------------------------------------
+	dataBytes, err := codec.Decode([]byte(dataString))
+	if err != nil {
+		return "", err
+	}
+	//--------------------------------------------------
+	return string(dataBytes), nil
+	//--------------------------------------------------
+}
+
+//--------------------------------------------------------------------------------
+// FormatFromContentType => payload format ("json", "cbor" or "msgpack") for a content type
+//--------------------------------------------------------------------------------
+
+func FormatFromContentType(contentType string) string {
+	//--------------------------------------------------
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	//--------------------------------------------------
+	switch mediaType {
+	case "application/cbor":
+		return conv.FORMAT_CBOR
+	case "application/msgpack", "application/x-msgpack", "application/vnd.msgpack":
+		return conv.FORMAT_MSGPACK
+	}
+	//--------------------------------------------------
+	return conv.FORMAT_JSON
+	//--------------------------------------------------
+}
+
+//--------------------------------------------------------------------------------
+// isJSONFormat
+//--------------------------------------------------------------------------------
+
+func (rpcObject *RPCStruct) isJSONFormat() bool {
+	//--------------------------------------------------
+	return rpcObject.Format == "" || strings.EqualFold(rpcObject.Format, conv.FORMAT_JSON)
+	//--------------------------------------------------
+}
+
+//--------------------------------------------------------------------------------
+// contentType => content type matching Format
+//--------------------------------------------------------------------------------
+
+func (rpcObject *RPCStruct) contentType() string {
+	//--------------------------------------------------
+	switch strings.ToLower(rpcObject.Format) {
+	case conv.FORMAT_CBOR:
+		return ContentTypeCBOR
+	case conv.FORMAT_MSGPACK:
+		return ContentTypeMsgPack
+	}
+	//--------------------------------------------------
+	return ContentTypeJSON
+	//--------------------------------------------------
+}
+
+//--------------------------------------------------------------------------------
+// errorJSON => {"error":"..."} with the message json escaped
+//--------------------------------------------------------------------------------
+
+func errorJSON(err error) string {
+	//--------------------------------------------------
+	dataBytes, _ := conv.JSON_Marshal(map[string]any{"error": err.Error()})
+	//--------------------------------------------------
+	return string(dataBytes)
+	//--------------------------------------------------
+}
+
+//--------------------------------------------------------------------------------
+// marshalMap => encodes a request / response map using Format
+//--------------------------------------------------------------------------------
+
+func (rpcObject *RPCStruct) marshalMap(dataMap map[string]any) (string, error) {
+	//--------------------------------------------------
+	if rpcObject.isJSONFormat() {
+		return RPC_encode_json(dataMap)
+	}
+	//--------------------------------------------------
+	dataBytes, err := conv.MarshalFormat(rpcObject.Format, dataMap)
+	if err != nil {
+		return "", err
+	}
+	//--------------------------------------------------
+	return string(dataBytes), nil
+	//--------------------------------------------------
+}
+
+//--------------------------------------------------------------------------------
+// unmarshalMap => decodes a request / response map using Format
+//--------------------------------------------------------------------------------
+
+func (rpcObject *RPCStruct) unmarshalMap(dataString string) (map[string]any, error) {
+	//--------------------------------------------------
+	if rpcObject.isJSONFormat() {
+		return RPC_decode_json(dataString)
+	}
+	//--------------------------------------------------
+	dataInterface, err := conv.UnmarshalFormat(rpcObject.Format, []byte(dataString))
+	if err != nil {
+		return map[string]any{}, err
+	}
+	//--------------------------------------------------
+	if !isObject(dataInterface) {
+		return map[string]any{}, errors.New("parse error")
+	}
+	//--------------------------------------------------
+	return dataInterface.(map[string]any), nil
+	//--------------------------------------------------
+}
+
+//--------------------------------------------------------------------------------
+//################################################################################
+//--------------------------------------------------------------------------------
+
 //--------------------------------------------------------------------------------
 // GetRemoteIPAddr
 //--------------------------------------------------------------------------------
@@ -56,6 +56,21 @@ func RPC_encode_json(jsonMap map[string]any) (string, error) {
 	//--------------------------------------------------
 }
 
+//--------------------------------------------------------------------------------
+// RPC_encode_json_canonical => canonical json (RFC 8785) for hashing / signing requests
+//--------------------------------------------------------------------------------
+
+func RPC_encode_json_canonical(jsonMap map[string]any) (string, error) {
+	//--------------------------------------------------
+	jsonBytes, err := conv.JSON_Canonical(jsonMap)
+	if err != nil {
+		return "", err
+	}
+	//--------------------------------------------------
+	return string(jsonBytes), nil
+	//--------------------------------------------------
+}
+
 //--------------------------------------------------------------------------------
 //################################################################################
 //--------------------------------------------------------------------------------
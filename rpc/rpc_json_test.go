@@ -138,6 +138,27 @@ func TestRPC_encode_json(t *testing.T) {
 	//--------------------------------------------------
 }
 
+//--------------------------------------------------------------------------------
+// RPC_encode_json_canonical
+//--------------------------------------------------------------------------------
+
+func TestRPC_encode_json_canonical(t *testing.T) {
+	//--------------------------------------------------
+	JSON := map[string]any{"params": []any{1.0, "<b>"}, "method": "echo", "id": 10}
+	EXPECTED_RESULT := `{"id":10,"method":"echo","params":[1,"<b>"]}`
+	//----------------------------------------
+	result, err := RPC_encode_json_canonical(JSON)
+	//--------------------------------------------------
+	if err != nil {
+		t.Error(err)
+	} else {
+		if result != EXPECTED_RESULT {
+			t.Errorf("result = %q but should = %q", result, EXPECTED_RESULT)
+		}
+	}
+	//--------------------------------------------------
+}
+
 //--------------------------------------------------------------------------------
 //################################################################################
 //--------------------------------------------------------------------------------
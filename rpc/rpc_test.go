@@ -71,7 +71,7 @@ func TestRPC_send_request_method(t *testing.T) {
 			}
 			//--------------------
 			if err != nil {
-				responseString = fmt.Sprintf(`{"error":%q}`, err)
+				responseString = errorJSON(err)
 			} else {
 				responseString = fmt.Sprintf(`{"content_type":%q,"request":%q}`, contentType, requestString)
 			}
@@ -148,7 +148,7 @@ func TestRPC_send_request_method_base64(t *testing.T) {
 			}
 			//--------------------
 			if err != nil {
-				responseString = fmt.Sprintf(`{"error":%q}`, err)
+				responseString = errorJSON(err)
 			} else {
 				responseString = fmt.Sprintf(`{"content_type":%q,"request":%q}`, contentType, requestString)
 			}
@@ -227,7 +227,7 @@ func TestRPC_send_request_method_base64url(t *testing.T) {
 			}
 			//--------------------
 			if err != nil {
-				responseString = fmt.Sprintf(`{"error":%q}`, err)
+				responseString = errorJSON(err)
 			} else {
 				responseString = fmt.Sprintf(`{"content_type":%q,"request":%q}`, contentType, requestString)
 			}
@@ -277,6 +277,59 @@ func TestRPC_send_request_method_base64url(t *testing.T) {
 	//--------------------------------------------------
 }
 
+//--------------------------------------------------------------------------------
+// send request method - registered codec
+//--------------------------------------------------------------------------------
+
+func TestRPC_send_request_method_codec(t *testing.T) {
+
+	//--------------------------------------------------
+	codec, _ := conv.LookupCodec("base91-escaped")
+	//--------------------
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestBytes, _ := io.ReadAll(httpRequest.Body)
+		requestBytes, err := codec.Decode(requestBytes)
+		if err != nil {
+			responseWriter.Write(codec.Encode([]byte(errorJSON(err))))
+		} else {
+			responseWriter.Write(codec.Encode([]byte(fmt.Sprintf(`{"request":%q}`, string(requestBytes)))))
+		}
+	}))
+	//--------------------
+	defer server.Close()
+	//--------------------------------------------------
+	requestString := "<REQUEST_DATA> \"$`"
+	//--------------------
+	EXPECTED_responseString := fmt.Sprintf(`{"request":%q}`, requestString)
+	//--------------------------------------------------
+
+	//--------------------------------------------------
+	codecObject := RPCStruct{Encoding: "base91-escaped", ResponseURL: server.URL, ResponseHeadersMap: map[string]string{}}
+	//--------------------------------------------------
+	responseString, err := codecObject.RPC_send_request(requestString)
+	//--------------------------------------------------
+
+	//--------------------------------------------------
+	if err != nil {
+
+		t.Error(err)
+
+	} else if responseString != EXPECTED_responseString {
+
+		t.Errorf("response = %q but should = %q", responseString, EXPECTED_responseString)
+	}
+	//--------------------------------------------------
+	codecObject.Encoding = "unknown"
+	//--------------------
+	_, err = codecObject.RPC_send_request(requestString)
+	//--------------------
+	if err == nil {
+
+		t.Error("unknown encoding should return an error")
+	}
+	//--------------------------------------------------
+}
+
 //--------------------------------------------------------------------------------
 // send json request method
 //--------------------------------------------------------------------------------
@@ -339,6 +392,49 @@ func TestRPC_send_json_request_method(t *testing.T) {
 	//--------------------------------------------------
 }
 
+func TestRPC_send_json_request_method_format(t *testing.T) {
+
+	//--------------------------------------------------
+	server := httptest.NewServer(http.HandlerFunc(RPC_Handler))
+	//--------------------
+	defer server.Close()
+	//--------------------------------------------------
+	requestMap := map[string]any{"method": "echo", "params": []any{1, "<b>"}}
+	//--------------------
+	EXPECTED_response := `{"method":"echo","params":[1,"<b>"]}`
+	//--------------------------------------------------
+	for _, format := range []string{"cbor", "msgpack"} {
+
+		//--------------------------------------------------
+		formatObject := RPCStruct{Format: format, ResponseURL: server.URL, ResponseHeadersMap: map[string]string{}}
+		//--------------------------------------------------
+		responseMap, err := formatObject.RPC_send_json_request(requestMap)
+		//--------------------------------------------------
+		if err != nil {
+
+			t.Error(err)
+
+		} else {
+
+			//--------------------
+			response, _ := conv.JSON_encode(responseMap)
+			//--------------------
+			if response != EXPECTED_response {
+
+				t.Errorf("(%s) response = %q but should = %q", format, response, EXPECTED_response)
+			}
+			//--------------------
+		}
+		//--------------------------------------------------
+	}
+	//--------------------------------------------------
+	if FormatFromContentType(ContentTypeMsgPack) != "msgpack" || FormatFromContentType(ContentTypeText) != "json" {
+
+		t.Error("FormatFromContentType returned the wrong format")
+	}
+	//--------------------------------------------------
+}
+
 //--------------------------------------------------------------------------------
 // read request method
 //--------------------------------------------------------------------------------
@@ -1706,6 +1802,27 @@ func TestJSONRPC_Handler(t *testing.T) {
 	//------------------------------------------------------------
 }
 
+//--------------------------------------------------------------------------------
+// errorJSON
+//--------------------------------------------------------------------------------
+
+func TestErrorJSON(t *testing.T) {
+	//------------------------------------------------------------
+	// %q would write "\x00" which is not a valid json escape
+	responseString := errorJSON(fmt.Errorf("bad byte \x00 <\"quoted\">"))
+	//------------------------------------------------------------
+	var responseMap map[string]any
+	//--------------------
+	if err := json.Unmarshal([]byte(responseString), &responseMap); err != nil {
+		t.Fatalf("response = %s is not valid json: %v", responseString, err)
+	}
+	//------------------------------------------------------------
+	if responseMap["error"] != "bad byte \x00 <\"quoted\">" {
+		t.Errorf("error = %q but should = %q", responseMap["error"], "bad byte \x00 <\"quoted\">")
+	}
+	//------------------------------------------------------------
+}
+
 //--------------------------------------------------------------------------------
 //################################################################################
 //--------------------------------------------------------------------------------
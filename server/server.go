@@ -25,8 +25,10 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/timbrockley/golang-main/conv"
 	"github.com/timbrockley/golang-main/file"
 	"github.com/timbrockley/golang-main/rpc"
+	"github.com/timbrockley/golang-main/system"
 	"golang.org/x/exp/slices"
 )
 
@@ -83,6 +85,23 @@ type SocketStruct struct {
 	//--------------------
 	KeepAlive bool
 	//--------------------
+	// payload format sent in the extended header: "" (not sent), "json", "cbor" or "msgpack"
+	Format string
+	//--------------------
+}
+
+//----------------------------------------
+
+// payload format ids (first byte of the optional extended header)
+const SocketFormatNone uint8 = 0
+const SocketFormatJSON uint8 = 1
+const SocketFormatCBOR uint8 = 2
+const SocketFormatMsgPack uint8 = 3
+
+var socketFormats = map[uint8]string{
+	SocketFormatJSON:    conv.FORMAT_JSON,
+	SocketFormatCBOR:    conv.FORMAT_CBOR,
+	SocketFormatMsgPack: conv.FORMAT_MSGPACK,
 }
 
 //------------------------------------------------------------
@@ -96,6 +115,17 @@ const contentTypeJavascript string = "application/javascript; charset=UTF-8"
 
 var flags = map[string]any{}
 
+//------------------------------------------------------------
+
+// unknown flags (such as the go test flags) are ignored
+var serverCLI = system.CLICommand{
+	Name: "server",
+	Flags: []system.CLIFlag{
+		{Name: "debug", Bool: true, Usage: "enable debug output"},
+	},
+	AllowUnknown: true,
+}
+
 //------------------------------------------------------------
 //############################################################
 //------------------------------------------------------------
@@ -103,30 +133,19 @@ var flags = map[string]any{}
 func init() {
 
 	//--------------------------------------------------
-	// set debug / test flags
+	// set debug / test flags (--debug, --debug=false, debug or debug=true)
 	//--------------------------------------------------
-	debugTrue := []string{"--debug", "--debug=true", "debug", "debug=true"}
-	debugFalse := []string{"--debug=false", "debug=false"}
+	cliArgs, _ := system.ParseCLI(serverCLI, os.Args[1:])
 	//--------------------
-	for i := 1; i < len(os.Args); i++ {
-		//--------------------
-		if strings.HasPrefix(os.Args[i], "--debug") || strings.HasPrefix(os.Args[i], "debug") {
-			//--------------------
-			if slices.Contains(debugTrue, os.Args[i]) {
-				flags["debug"] = true
-			} else if slices.Contains(debugFalse, os.Args[i]) {
-				flags["debug"] = false
-			}
-			//--------------------
-		}
-		//--------------------
-		if os.Args[i] == "-test.v=true" {
-			flags["test"] = true
-		}
-		//--------------------
+	if slices.Contains(cliArgs.Args, "-test.v=true") {
+		flags["test"] = true
+	}
+	//--------------------
+	if cliArgs.IsSet("debug") {
+		flags["debug"] = cliArgs.Bool("debug")
 	}
 	//--------------------------------------------------
-	// check if environment variable set
+	// check if environment variable set (only "true" is recognised)
 	if flags["debug"] == nil && strings.EqualFold(os.Getenv("GOLANG_DEBUG"), "true") {
 		flags["debug"] = true
 	}
@@ -626,6 +645,11 @@ func (socketObject *SocketStruct) SocketServerEcho() error {
 						}
 						//--------------------
 						if err == nil {
+							//--------------------
+							requestFormat := ""
+							if extendedHeaderLength > 0 {
+								requestFormat = socketFormats[extendedbaseHeaderBytes[0]]
+							}
 							//--------------------
 							requestBytes = make([]byte, bodyLength)
 							bytesRead, err = conn.Read(requestBytes)
@@ -633,7 +657,13 @@ func (socketObject *SocketStruct) SocketServerEcho() error {
 							if bytesRead > 0 {
 								//--------------------
 								if flags["debug"] == true {
-									fmt.Printf("client request: %s\n", string(requestBytes))
+									if requestFormat != "" && requestFormat != conv.FORMAT_JSON {
+										requestInterface, _ := conv.UnmarshalFormat(requestFormat, requestBytes[:bytesRead])
+										requestString, _ := conv.JSON_encode(requestInterface)
+										fmt.Printf("client request (%s): %s\n", requestFormat, requestString)
+									} else {
+										fmt.Printf("client request: %s\n", string(requestBytes))
+									}
 								}
 								//--------------------
 								conn.Write(requestBytes)
@@ -666,7 +696,12 @@ func (socketObject *SocketStruct) SocketClient(requestBytes []byte) ([]byte, err
 	//--------------------------------------------------
 	var err error
 	var combinedRequestBytes, responseBytes []byte
-	var headerLength uint8
+	var headerLength, formatID uint8
+	//--------------------------------------------------
+	formatID, err = socketFormatID(socketObject.Format)
+	if err != nil {
+		return nil, err
+	}
 	//--------------------------------------------------
 	socketObject.Conn, err = net.Dial("unix", socketObject.Addr)
 	//--------------------------------------------------
@@ -676,13 +711,18 @@ func (socketObject *SocketStruct) SocketClient(requestBytes []byte) ([]byte, err
 		//--------------------
 		headerLength = 5 // (base header length + optional extended header length)
 		//--------------------
+		if formatID != SocketFormatNone {
+			headerLength++
+		}
+		//--------------------
 		combinedRequestBytes = make([]byte, int(headerLength)+len(requestBytes))
 		combinedRequestBytes[0] = headerLength
 		binary.BigEndian.PutUint32(combinedRequestBytes[1:5], uint32(len(requestBytes)))
 		//--------------------
-		/*
-			create optional extended header here
-		*/
+		// optional extended header
+		if formatID != SocketFormatNone {
+			combinedRequestBytes[5] = formatID
+		}
 		//--------------------
 		copy(combinedRequestBytes[headerLength:], requestBytes)
 		//--------------------
@@ -698,6 +738,52 @@ func (socketObject *SocketStruct) SocketClient(requestBytes []byte) ([]byte, err
 	//--------------------------------------------------
 }
 
+//------------------------------------------------------------
+
+func (socketObject *SocketStruct) SocketClientMap(requestMap map[string]any) (map[string]any, error) {
+	//--------------------------------------------------
+	requestBytes, err := conv.MarshalFormat(socketObject.Format, requestMap)
+	if err != nil {
+		return nil, err
+	}
+	//--------------------------------------------------
+	responseBytes, err := socketObject.SocketClient(requestBytes)
+	if err != nil {
+		return nil, err
+	}
+	//--------------------------------------------------
+	responseInterface, err := conv.UnmarshalFormat(socketObject.Format, responseBytes)
+	if err != nil {
+		return nil, err
+	}
+	//--------------------------------------------------
+	responseMap, ok := responseInterface.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("response is not an object")
+	}
+	//--------------------------------------------------
+	return responseMap, nil
+	//--------------------------------------------------
+}
+
+//------------------------------------------------------------
+
+func socketFormatID(format string) (uint8, error) {
+	//--------------------------------------------------
+	if format == "" {
+		return SocketFormatNone, nil
+	}
+	//--------------------------------------------------
+	for formatID, formatName := range socketFormats {
+		if strings.EqualFold(format, formatName) {
+			return formatID, nil
+		}
+	}
+	//--------------------------------------------------
+	return SocketFormatNone, fmt.Errorf("unknown format: %s", format)
+	//--------------------------------------------------
+}
+
 //------------------------------------------------------------
 //############################################################
 //------------------------------------------------------------
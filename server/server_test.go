@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"regexp"
 	"sync"
 	"testing"
@@ -557,6 +558,70 @@ func TestSocketClient(t *testing.T) {
 	//--------------------------------------------------
 }
 
+//------------------------------------------------------------
+
+func TestSocketClientMap(t *testing.T) {
+
+	//--------------------------------------------------
+	// SocketClientMap
+	//--------------------------------------------------
+	requestMap := map[string]any{"method": "echo", "params": []any{1, 2.5, "<b>"}}
+	//--------------------
+	EXPECTED_response := fmt.Sprint(requestMap)
+	//--------------------------------------------------
+	for _, format := range []string{"json", "cbor", "msgpack"} {
+		//--------------------------------------------------
+		socketAddr := "golang-socket-test-" + format + ".sock"
+		//--------
+		socketObject := SocketStruct{Addr: socketAddr, Format: format}
+		//--------------------------------------------------
+		wg := sync.WaitGroup{}
+		//--------
+		wg.Add(1)
+		//--------------------------------------------------
+		go func() {
+			//--------------------------------------------------
+			wg.Done()
+			//--------
+			err := socketObject.SocketServerEcho()
+			//--------
+			if err != nil {
+				t.Error(err)
+			}
+			//--------------------------------------------------
+		}()
+		//--------------------------------------------------
+		wg.Wait()
+		//--------------------
+		time.Sleep(100 * time.Millisecond)
+		//--------------------------------------------------
+		responseMap, err := socketObject.SocketClientMap(requestMap)
+		//--------------------------------------------------
+		if err != nil {
+			t.Error(err)
+		} else {
+			//--------------------
+			// numbers come back as float64 (json) or int64 (cbor / msgpack) so compare printed values
+			response := fmt.Sprint(responseMap)
+			//--------------------
+			if response != EXPECTED_response {
+				t.Errorf("(%s) response = %q but should = %q", format, response, EXPECTED_response)
+			}
+			//--------------------
+		}
+		//--------------------------------------------------
+		os.Remove(socketAddr)
+		//--------------------------------------------------
+	}
+	//--------------------------------------------------
+	socketObject := SocketStruct{Addr: "golang-socket-test-unknown.sock", Format: "xml"}
+	//--------------------
+	if _, err := socketObject.SocketClient([]byte("test")); err == nil {
+		t.Error("unknown format should return an error")
+	}
+	//--------------------------------------------------
+}
+
 //------------------------------------------------------------
 //############################################################
 //------------------------------------------------------------
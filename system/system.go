@@ -10,9 +10,6 @@ LICENSE file in the root directory of this source tree.
 package system
 
 import (
-	"crypto/hmac"
-	"crypto/sha1"
-	"encoding/base32"
 	"errors"
 	"fmt"
 	"io"
@@ -21,6 +18,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/timbrockley/golang-main/file"
@@ -272,20 +270,41 @@ func ToBool(value any) bool {
 }
 
 //------------------------------------------------------------
-// CopyMap
+// ToTime => see ToTimeE for accepted values (zero time if invalid)
+//------------------------------------------------------------
+
+func ToTime(value any) time.Time {
+	//------------------------------------------------------------
+	timeVal, _ := ToTimeE(value)
+	//------------------------------------------------------------
+	return timeVal
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToDuration => see ToDurationE for accepted values (0 if invalid)
+//------------------------------------------------------------
+
+func ToDuration(value any) time.Duration {
+	//------------------------------------------------------------
+	duration, _ := ToDurationE(value)
+	//------------------------------------------------------------
+	return duration
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CopyMap => deep copy including nested slices (see DeepCopyMap)
 //------------------------------------------------------------
 
 func CopyMap(m map[string]interface{}) map[string]interface{} {
-	newMap := make(map[string]interface{})
-	for k, v := range m {
-		vm, ok := v.(map[string]interface{})
-		if ok {
-			newMap[k] = CopyMap(vm)
-		} else {
-			newMap[k] = v
-		}
+	//------------------------------------------------------------
+	if m == nil {
+		return map[string]interface{}{}
 	}
-	return newMap
+	//------------------------------------------------------------
+	return DeepCopyMap(m)
+	//------------------------------------------------------------
 }
 
 //------------------------------------------------------------
@@ -506,7 +525,7 @@ func GetLocalIPAddr() string {
 	//------------------------------------------------------------
 	ip_addrs, _ := GetLocalIPs()
 	//--------------------
-	if fmt.Sprintf("%T", ip_addrs) == "[]net.IP" && ip_addrs[0] != nil {
+	if len(ip_addrs) > 0 && ip_addrs[0] != nil {
 		ip_addr = fmt.Sprintf("%v", ip_addrs[0])
 	} else {
 		ip_addr = "127.0.0.1"
@@ -576,7 +595,7 @@ func CLIParam(index int) string {
 //------------------------------------------------------------
 
 //------------------------------------------------------------
-// GenerateOTP
+// GenerateOTP => SHA1, 6 digit, 30 second TOTP (see OTP for other options)
 //------------------------------------------------------------
 
 func GenerateOTP(timestamp int, secret string) (string, error) {
@@ -585,32 +604,10 @@ func GenerateOTP(timestamp int, secret string) (string, error) {
 		return "", errors.New("invalid timestamp")
 	}
 	if secret == "" {
-		return "", errors.New("invalid secret")
+		return "", ErrOTPSecret
 	}
 	//------------------------------------------------------------
-	message := make([]byte, 8)
-	for i := 7; i >= 0; i-- {
-		message[i] = byte(timestamp / 30)
-		timestamp >>= 8
-	}
-	//------------------------------------------------------------
-	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
-	if err != nil {
-		return "", errors.New("secret contains invalid base32 characters")
-	}
-	//------------------------------------------------------------
-	hash := hmac.New(sha1.New, key)
-	hash.Write(message)
-	hmacResult := hash.Sum(nil)
-	//------------------------------------------------------------
-	offset := hmacResult[len(hmacResult)-1] & 0xF
-	code := (int(hmacResult[offset+0]&0x7F) << 24) |
-		(int(hmacResult[offset+1]&0xFF) << 16) |
-		(int(hmacResult[offset+2]&0xFF) << 8) |
-		(int(hmacResult[offset+3] & 0xFF))
-	//------------------------------------------------------------
-	otp := code % 1000000
-	return fmt.Sprintf("%06d", otp), nil
+	return OTP{Secret: secret}.TOTP(time.Unix(int64(timestamp), 0))
 	// ------------------------------------------------------------
 }
 
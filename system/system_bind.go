@@ -0,0 +1,467 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"database/sql"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------
+
+// tags checked in order when tagNames are not passed to Bind
+var BindTags = []string{"db", "yaml", "json"}
+
+//------------------------------------------------------------
+
+var ErrBindTarget = errors.New("bind target must be a non-nil pointer")
+
+//------------------------------------------------------------
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+//------------------------------------------------------------
+// BindFieldError => a single field that could not be set
+//------------------------------------------------------------
+
+type BindFieldError struct {
+	Field string // struct field path (Address.City, [2].Name)
+	Key   string // map key the value was read from
+	Err   error
+}
+
+//------------------------------------------------------------
+// BindFieldError - Error
+//------------------------------------------------------------
+
+func (e BindFieldError) Error() string {
+	//------------------------------------------------------------
+	return fmt.Sprintf("%s (key %q): %v", e.Field, e.Key, e.Err)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BindFieldError - Unwrap
+//------------------------------------------------------------
+
+func (e BindFieldError) Unwrap() error {
+	//------------------------------------------------------------
+	return e.Err
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BindError => every field that failed during Bind or BindSlice
+//------------------------------------------------------------
+
+type BindError struct {
+	Fields []BindFieldError
+}
+
+//------------------------------------------------------------
+// BindError - Error
+//------------------------------------------------------------
+
+func (e BindError) Error() string {
+	//------------------------------------------------------------
+	messages := make([]string, len(e.Fields))
+	//--------------------
+	for index, fieldError := range e.Fields {
+		messages[index] = fieldError.Error()
+	}
+	//------------------------------------------------------------
+	return fmt.Sprintf("bind failed for %d field(s): %s", len(e.Fields), strings.Join(messages, "; "))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BindError - Unwrap
+//------------------------------------------------------------
+
+func (e BindError) Unwrap() []error {
+	//------------------------------------------------------------
+	errs := make([]error, len(e.Fields))
+	//--------------------
+	for index, fieldError := range e.Fields {
+		errs[index] = fieldError
+	}
+	//------------------------------------------------------------
+	return errs
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Bind => copies dataMap into the struct pointed to by target
+//------------------------------------------------------------
+// keys are matched using tagNames (defaults to BindTags) and then the
+// field name (case-insensitive), values are coerced with the To*E functions,
+// sql.Scanner and encoding.TextUnmarshaler fields are also supported
+//------------------------------------------------------------
+
+func Bind(dataMap map[string]any, target any, tagNames ...string) error {
+	//------------------------------------------------------------
+	reflectValue := reflect.ValueOf(target)
+	//------------------------------------------------------------
+	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w to a struct (got %T)", ErrBindTarget, target)
+	}
+	//------------------------------------------------------------
+	if len(tagNames) == 0 {
+		tagNames = BindTags
+	}
+	//------------------------------------------------------------
+	var fieldErrors []BindFieldError
+	//------------------------------------------------------------
+	bindStruct(reflectValue.Elem(), dataMap, tagNames, "", &fieldErrors)
+	//------------------------------------------------------------
+	if len(fieldErrors) > 0 {
+		return BindError{Fields: fieldErrors}
+	}
+	//------------------------------------------------------------
+	return nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BindSlice => binds each map in records to a new element appended to *target
+//------------------------------------------------------------
+// target must be a pointer to a slice of structs or struct pointers
+//------------------------------------------------------------
+
+func BindSlice(records []map[string]any, target any, tagNames ...string) error {
+	//------------------------------------------------------------
+	reflectValue := reflect.ValueOf(target)
+	//------------------------------------------------------------
+	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w to a slice (got %T)", ErrBindTarget, target)
+	}
+	//------------------------------------------------------------
+	sliceValue := reflectValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	//--------------------
+	if isPointer {
+		elemType = elemType.Elem()
+	}
+	//--------------------
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("%w to a slice of structs (got %T)", ErrBindTarget, target)
+	}
+	//------------------------------------------------------------
+	if len(tagNames) == 0 {
+		tagNames = BindTags
+	}
+	//------------------------------------------------------------
+	var fieldErrors []BindFieldError
+	//------------------------------------------------------------
+	for index, record := range records {
+		//--------------------
+		elemValue := reflect.New(elemType)
+		//--------------------
+		bindStruct(elemValue.Elem(), record, tagNames, fmt.Sprintf("[%d].", index), &fieldErrors)
+		//--------------------
+		if isPointer {
+			sliceValue.Set(reflect.Append(sliceValue, elemValue))
+		} else {
+			sliceValue.Set(reflect.Append(sliceValue, elemValue.Elem()))
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if len(fieldErrors) > 0 {
+		return BindError{Fields: fieldErrors}
+	}
+	//------------------------------------------------------------
+	return nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// bindStruct
+//------------------------------------------------------------
+
+func bindStruct(structValue reflect.Value, dataMap map[string]any, tagNames []string, path string, fieldErrors *[]BindFieldError) {
+	//------------------------------------------------------------
+	structType := structValue.Type()
+	//------------------------------------------------------------
+	for index := 0; index < structType.NumField(); index++ {
+		//------------------------------------------------------------
+		field := structType.Field(index)
+		fieldValue := structValue.Field(index)
+		//------------------------------------------------------------
+		key, tagged := bindKey(field, tagNames)
+		//--------------------
+		if key == "-" {
+			continue
+		}
+		//------------------------------------------------------------
+		// untagged embedded structs share the parent map
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			bindStruct(fieldValue, dataMap, tagNames, path, fieldErrors)
+			continue
+		}
+		//--------------------
+		if !field.IsExported() {
+			continue
+		}
+		//------------------------------------------------------------
+		value, found := bindLookup(dataMap, key, tagged)
+		//--------------------
+		if !found {
+			continue
+		}
+		//------------------------------------------------------------
+		bindValue(fieldValue, value, tagNames, path+field.Name, key, fieldErrors)
+		//------------------------------------------------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// bindKey => map key for field and whether it came from a tag
+//------------------------------------------------------------
+
+func bindKey(field reflect.StructField, tagNames []string) (string, bool) {
+	//------------------------------------------------------------
+	for _, tagName := range tagNames {
+		//--------------------
+		tag, found := field.Tag.Lookup(tagName)
+		//--------------------
+		if !found {
+			continue
+		}
+		//--------------------
+		name, _, _ := strings.Cut(tag, ",")
+		//--------------------
+		if name != "" {
+			return name, true
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return field.Name, false
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// bindLookup => untagged fields fall back to a case-insensitive match
+//------------------------------------------------------------
+
+func bindLookup(dataMap map[string]any, key string, tagged bool) (any, bool) {
+	//------------------------------------------------------------
+	if value, found := dataMap[key]; found {
+		return value, true
+	}
+	//------------------------------------------------------------
+	if !tagged {
+		for mapKey, value := range dataMap {
+			if strings.EqualFold(mapKey, key) {
+				return value, true
+			}
+		}
+	}
+	//------------------------------------------------------------
+	return nil, false
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// bindValue => sets fieldValue from value, recording any error
+//------------------------------------------------------------
+
+func bindValue(fieldValue reflect.Value, value any, tagNames []string, path string, key string, fieldErrors *[]BindFieldError) {
+	//------------------------------------------------------------
+	addError := func(err error) {
+		*fieldErrors = append(*fieldErrors, BindFieldError{Field: path, Key: key, Err: err})
+	}
+	//------------------------------------------------------------
+	fieldType := fieldValue.Type()
+	//------------------------------------------------------------
+	if value == nil {
+		fieldValue.SetZero()
+		return
+	}
+	//------------------------------------------------------------
+	if reflect.TypeOf(value).AssignableTo(fieldType) {
+		fieldValue.Set(reflect.ValueOf(value))
+		return
+	}
+	//------------------------------------------------------------
+	// time.Time is a TextUnmarshaler but ToTimeE accepts more layouts
+	if fieldType != timeType && fieldValue.CanAddr() {
+		//--------------------
+		switch typedField := fieldValue.Addr().Interface().(type) {
+		case sql.Scanner:
+			if err := typedField.Scan(value); err != nil {
+				addError(err)
+			}
+			return
+		case encoding.TextUnmarshaler:
+			if text, err := ToStringE(value); err == nil {
+				if err = typedField.UnmarshalText([]byte(text)); err != nil {
+					addError(err)
+				}
+				return
+			}
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	var converted any
+	var err error
+	//------------------------------------------------------------
+	switch fieldType {
+	case timeType:
+		converted, err = ToTimeE(value)
+	case durationType:
+		converted, err = ToDurationE(value)
+	default:
+		//------------------------------------------------------------
+		switch fieldType.Kind() {
+		case reflect.String:
+			converted, err = ToStringE(value)
+		case reflect.Bool:
+			converted, err = ToBoolE(value)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			converted, err = convertSigned[int64](value, fieldType.Bits())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			converted, err = convertUnsigned[uint64](value, fieldType.Bits())
+		case reflect.Float32:
+			converted, err = ToFloat32E(value)
+		case reflect.Float64:
+			converted, err = ToFloat64E(value)
+		case reflect.Pointer:
+			//--------------------
+			elemValue := reflect.New(fieldType.Elem())
+			errorCount := len(*fieldErrors)
+			//--------------------
+			bindValue(elemValue.Elem(), value, tagNames, path, key, fieldErrors)
+			//--------------------
+			if len(*fieldErrors) == errorCount {
+				fieldValue.Set(elemValue)
+			}
+			//--------------------
+			return
+		case reflect.Struct:
+			//--------------------
+			nestedMap, ok := value.(map[string]any)
+			if !ok {
+				addError(fmt.Errorf("%w: cannot bind %T to %s", ErrConvert, value, fieldType))
+				return
+			}
+			//--------------------
+			bindStruct(fieldValue, nestedMap, tagNames, path+".", fieldErrors)
+			//--------------------
+			return
+		case reflect.Slice:
+			bindSlice(fieldValue, value, tagNames, path, key, fieldErrors)
+			return
+		case reflect.Map:
+			bindMap(fieldValue, value, tagNames, path, key, fieldErrors)
+			return
+		default:
+			err = fmt.Errorf("%w: unsupported field type %s", ErrConvert, fieldType)
+		}
+		//------------------------------------------------------------
+	}
+	//------------------------------------------------------------
+	if err != nil {
+		addError(err)
+		return
+	}
+	//------------------------------------------------------------
+	fieldValue.Set(reflect.ValueOf(converted).Convert(fieldType))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// bindSlice => binds any slice (or string for []byte fields)
+//------------------------------------------------------------
+
+func bindSlice(fieldValue reflect.Value, value any, tagNames []string, path string, key string, fieldErrors *[]BindFieldError) {
+	//------------------------------------------------------------
+	fieldType := fieldValue.Type()
+	//------------------------------------------------------------
+	if fieldType.Elem().Kind() == reflect.Uint8 {
+		if stringVal, ok := value.(string); ok {
+			fieldValue.Set(reflect.ValueOf([]byte(stringVal)).Convert(fieldType))
+			return
+		}
+	}
+	//------------------------------------------------------------
+	sourceValue := reflect.ValueOf(value)
+	//--------------------
+	if sourceValue.Kind() != reflect.Slice && sourceValue.Kind() != reflect.Array {
+		*fieldErrors = append(*fieldErrors, BindFieldError{Field: path, Key: key, Err: fmt.Errorf("%w: cannot bind %T to %s", ErrConvert, value, fieldType)})
+		return
+	}
+	//------------------------------------------------------------
+	sliceValue := reflect.MakeSlice(fieldType, sourceValue.Len(), sourceValue.Len())
+	//------------------------------------------------------------
+	for index := 0; index < sourceValue.Len(); index++ {
+		bindValue(sliceValue.Index(index), sourceValue.Index(index).Interface(), tagNames, fmt.Sprintf("%s[%d]", path, index), key, fieldErrors)
+	}
+	//------------------------------------------------------------
+	fieldValue.Set(sliceValue)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// bindMap => binds maps with string keys
+//------------------------------------------------------------
+
+func bindMap(fieldValue reflect.Value, value any, tagNames []string, path string, key string, fieldErrors *[]BindFieldError) {
+	//------------------------------------------------------------
+	fieldType := fieldValue.Type()
+	sourceValue := reflect.ValueOf(value)
+	//------------------------------------------------------------
+	if fieldType.Key().Kind() != reflect.String || sourceValue.Kind() != reflect.Map || sourceValue.Type().Key().Kind() != reflect.String {
+		*fieldErrors = append(*fieldErrors, BindFieldError{Field: path, Key: key, Err: fmt.Errorf("%w: cannot bind %T to %s", ErrConvert, value, fieldType)})
+		return
+	}
+	//------------------------------------------------------------
+	mapValue := reflect.MakeMapWithSize(fieldType, sourceValue.Len())
+	//------------------------------------------------------------
+	iterator := sourceValue.MapRange()
+	//--------------------
+	for iterator.Next() {
+		//--------------------
+		elemValue := reflect.New(fieldType.Elem()).Elem()
+		mapKey := iterator.Key().String()
+		//--------------------
+		bindValue(elemValue, iterator.Value().Interface(), tagNames, fmt.Sprintf("%s[%q]", path, mapKey), key, fieldErrors)
+		//--------------------
+		mapValue.SetMapIndex(reflect.ValueOf(mapKey).Convert(fieldType.Key()), elemValue)
+		//--------------------
+	}
+	//------------------------------------------------------------
+	fieldValue.Set(mapValue)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
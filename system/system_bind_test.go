@@ -0,0 +1,223 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+
+type bindTestAddress struct {
+	City     string `yaml:"city"`
+	Postcode string `yaml:"post_code"`
+}
+
+type bindTestBase struct {
+	ID UUID `db:"id"`
+}
+
+type bindTestUser struct {
+	bindTestBase
+	Name      string            `db:"name"`
+	Age       int32             `db:"age"`
+	Score     float64           `db:"score"`
+	Active    bool              `db:"active"`
+	Created   time.Time         `db:"created_at"`
+	Timeout   time.Duration     `yaml:"timeout"`
+	Nickname  *string           `db:"nickname"`
+	Tags      []string          `yaml:"tags"`
+	Limits    map[string]uint16 `yaml:"limits"`
+	Address   bindTestAddress   `yaml:"address"`
+	Extra     any               `db:"extra"`
+	Email     string
+	Ignored   string `db:"-"`
+	notString string
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Bind
+//------------------------------------------------------------
+
+func TestBind(t *testing.T) {
+	//------------------------------------------------------------
+	uuid, _ := NewUUIDv4()
+	//------------------------------------------------------------
+	dataMap := map[string]any{
+		"id":         uuid.String(),
+		"name":       "Tim",
+		"age":        "42",
+		"score":      7,
+		"active":     "yes",
+		"created_at": "2024-03-01 12:30:45",
+		"timeout":    "5m",
+		"nickname":   "tb",
+		"tags":       []any{"a", 2, true},
+		"limits":     map[string]any{"cpu": 2, "ram": "512"},
+		"address":    map[string]any{"city": "York", "post_code": "YO1"},
+		"extra":      []int{1},
+		"EMAIL":      "tim@example.com",
+		"-":          "x",
+		"Ignored":    "x",
+		"notString":  "x",
+	}
+	//------------------------------------------------------------
+	var user bindTestUser
+	//--------------------
+	if err := Bind(dataMap, &user); err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	if user.ID != uuid {
+		t.Errorf("ID = %s but should = %s", user.ID, uuid)
+	}
+	//--------------------
+	if user.Name != "Tim" || user.Age != 42 || user.Score != 7 || !user.Active {
+		t.Errorf("user = %+v", user)
+	}
+	//--------------------
+	if !user.Created.Equal(time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)) || user.Timeout != 5*time.Minute {
+		t.Errorf("Created = %v, Timeout = %v", user.Created, user.Timeout)
+	}
+	//--------------------
+	if user.Nickname == nil || *user.Nickname != "tb" {
+		t.Errorf("Nickname = %v but should = %q", user.Nickname, "tb")
+	}
+	//--------------------
+	if strings.Join(user.Tags, ",") != "a,2,true" {
+		t.Errorf("Tags = %q but should = %q", user.Tags, []string{"a", "2", "true"})
+	}
+	//--------------------
+	if user.Limits["cpu"] != 2 || user.Limits["ram"] != 512 {
+		t.Errorf("Limits = %v", user.Limits)
+	}
+	//--------------------
+	if user.Address.City != "York" || user.Address.Postcode != "YO1" {
+		t.Errorf("Address = %+v", user.Address)
+	}
+	//--------------------
+	if extra, ok := user.Extra.([]int); !ok || len(extra) != 1 {
+		t.Errorf("Extra = %#v", user.Extra)
+	}
+	//--------------------
+	if user.Email != "tim@example.com" || user.Ignored != "" || user.notString != "" {
+		t.Errorf("Email = %q, Ignored = %q, notString = %q", user.Email, user.Ignored, user.notString)
+	}
+	//------------------------------------------------------------
+	user.Nickname = nil
+	//--------------------
+	if err := Bind(map[string]any{"nickname": nil, "age": nil}, &user); err != nil || user.Nickname != nil || user.Age != 0 {
+		t.Errorf("NULL values should set zero values (err = %v)", err)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Bind errors
+//------------------------------------------------------------
+
+func TestBindErrors(t *testing.T) {
+	//------------------------------------------------------------
+	dataMap := map[string]any{
+		"id":      "not-a-uuid",
+		"age":     "3000000000",
+		"active":  "maybe",
+		"tags":    "a",
+		"address": map[string]any{"city": []int{1}},
+		"name":    "Tim",
+	}
+	//------------------------------------------------------------
+	var user bindTestUser
+	//--------------------
+	err := Bind(dataMap, &user)
+	//------------------------------------------------------------
+	var bindError BindError
+	//--------------------
+	if !errors.As(err, &bindError) {
+		t.Fatalf("err = %v but should be a BindError", err)
+	}
+	//------------------------------------------------------------
+	failedFields := map[string]bool{}
+	//--------------------
+	for _, fieldError := range bindError.Fields {
+		failedFields[fieldError.Field] = true
+	}
+	//--------------------
+	for _, field := range []string{"ID", "Age", "Active", "Tags", "Address.City"} {
+		if !failedFields[field] {
+			t.Errorf("field %s should have failed (%v)", field, err)
+		}
+	}
+	//--------------------
+	if len(bindError.Fields) != 5 {
+		t.Errorf("len(Fields) = %d but should = %d (%v)", len(bindError.Fields), 5, err)
+	}
+	//------------------------------------------------------------
+	if !errors.Is(err, ErrConvertOverflow) || !errors.Is(err, ErrUUIDInvalid) {
+		t.Errorf("err = %v should wrap the field errors", err)
+	}
+	//--------------------
+	if user.Name != "Tim" {
+		t.Errorf("Name = %q but should = %q (valid fields are still bound)", user.Name, "Tim")
+	}
+	//------------------------------------------------------------
+	for _, target := range []any{nil, user, &dataMap, (*bindTestUser)(nil)} {
+		if err := Bind(dataMap, target); !errors.Is(err, ErrBindTarget) {
+			t.Errorf("(%T) err = %v but should = %v", target, err, ErrBindTarget)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// BindSlice
+//------------------------------------------------------------
+
+func TestBindSlice(t *testing.T) {
+	//------------------------------------------------------------
+	records := []map[string]any{
+		{"name": "Tim", "age": int64(42)},
+		{"name": "Sam", "age": "x"},
+		{"name": "Alex", "age": 3.0},
+	}
+	//------------------------------------------------------------
+	var users []bindTestUser
+	//--------------------
+	err := BindSlice(records, &users, "db")
+	//------------------------------------------------------------
+	var bindError BindError
+	//--------------------
+	if !errors.As(err, &bindError) || len(bindError.Fields) != 1 || bindError.Fields[0].Field != "[1].Age" {
+		t.Errorf("err = %v but should only fail [1].Age", err)
+	}
+	//--------------------
+	if len(users) != 3 || users[0].Age != 42 || users[2].Name != "Alex" || users[2].Age != 3 {
+		t.Errorf("users = %+v", users)
+	}
+	//------------------------------------------------------------
+	var userPointers []*bindTestUser
+	//--------------------
+	if err := BindSlice(records[:1], &userPointers); err != nil {
+		t.Error(err)
+	} else if len(userPointers) != 1 || userPointers[0].Name != "Tim" {
+		t.Errorf("userPointers = %+v", userPointers)
+	}
+	//------------------------------------------------------------
+	var numbers []int
+	//--------------------
+	if err := BindSlice(records, &numbers); !errors.Is(err, ErrBindTarget) {
+		t.Errorf("err = %v but should = %v", err, ErrBindTarget)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
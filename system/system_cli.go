@@ -0,0 +1,573 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+//------------------------------------------------------------
+
+var (
+	ErrCLIHelp    = errors.New("help requested")
+	ErrCLIFlag    = errors.New("invalid flag")
+	ErrCLICommand = errors.New("invalid command")
+)
+
+//------------------------------------------------------------
+// CLIFlag
+//------------------------------------------------------------
+
+type CLIFlag struct {
+	// long name used as --name, --name=value and name=value
+	Name string
+	// optional single character used as -s, -s value and -s=value
+	Short string
+	// description shown in the help text
+	Usage string
+	// value used when the flag and Env are not set
+	Default string
+	// environment variable used when the flag is not given
+	Env string
+	// takes no value (--name, --no-name, --name=false)
+	Bool bool
+	// parsing fails if the flag, Env and Default are all unset
+	Required bool
+}
+
+//------------------------------------------------------------
+// CLICommand
+//------------------------------------------------------------
+
+type CLICommand struct {
+	Name  string
+	Usage string
+	// flags are inherited by subcommands
+	Flags    []CLIFlag
+	Commands []CLICommand
+	// called by Execute for the matched command
+	Run func(args CLIArgs) error
+	// unknown flags are kept in Args instead of returning an error
+	AllowUnknown bool
+}
+
+//------------------------------------------------------------
+// CLIArgs
+//------------------------------------------------------------
+
+type CLIArgs struct {
+	// names of the matched command and subcommands
+	Command []string
+	// flag values by long name (including Env and Default values)
+	Values map[string]string
+	// where each value came from ("flag", "env" or "default")
+	Sources map[string]string
+	// positional arguments
+	Args []string
+	//--------------------
+	command CLICommand
+	flags   []CLIFlag
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// ParseCLI => parses args (usually os.Args[1:]) against command
+//------------------------------------------------------------
+// accepts --name value, --name=value, -s value, -s=value, -abc (bool
+// shorts), --no-name (bool), name=value and -- to end the flags,
+// errors are joined so args still holds everything that could be parsed
+//------------------------------------------------------------
+
+func ParseCLI(command CLICommand, args []string) (CLIArgs, error) {
+	//------------------------------------------------------------
+	cliArgs := CLIArgs{
+		Command: []string{command.Name},
+		Values:  map[string]string{},
+		Sources: map[string]string{},
+		Args:    []string{},
+		command: command,
+		flags:   command.Flags,
+	}
+	//------------------------------------------------------------
+	var errs []error
+	var helpYesNo, positionalOnly bool
+	//--------------------
+	allowUnknown := command.AllowUnknown
+	//------------------------------------------------------------
+	setFlag := func(flag CLIFlag, value string) {
+		//--------------------
+		if flag.Bool {
+			//--------------------
+			boolValue, err := ToBoolE(value)
+			//--------------------
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: --%s=%s", ErrCLIFlag, flag.Name, value))
+				return
+			}
+			//--------------------
+			value = strconv.FormatBool(boolValue)
+			//--------------------
+		}
+		//--------------------
+		cliArgs.Values[flag.Name] = value
+		cliArgs.Sources[flag.Name] = "flag"
+		//--------------------
+	}
+	//------------------------------------------------------------
+	unknownFlag := func(arg string) {
+		//--------------------
+		if allowUnknown {
+			cliArgs.Args = append(cliArgs.Args, arg)
+		} else {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrCLIFlag, arg))
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	for index := 0; index < len(args); index++ {
+		//------------------------------------------------------------
+		arg := args[index]
+		//------------------------------------------------------------
+		switch {
+		//------------------------------------------------------------
+		case positionalOnly:
+			//--------------------
+			cliArgs.Args = append(cliArgs.Args, arg)
+			//------------------------------------------------------------
+		case arg == "--":
+			//--------------------
+			positionalOnly = true
+			//------------------------------------------------------------
+		case (arg == "--help" && !cliHasFlag(cliArgs.flags, "help", "")) || (arg == "-h" && !cliHasFlag(cliArgs.flags, "", "h")):
+			//--------------------
+			helpYesNo = true
+			//------------------------------------------------------------
+		case strings.HasPrefix(arg, "--"):
+			//------------------------------------------------------------
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			//--------------------
+			flag, found := cliFindFlag(cliArgs.flags, name, "")
+			//--------------------
+			if !found && strings.HasPrefix(name, "no-") {
+				if flag, found = cliFindFlag(cliArgs.flags, name[3:], ""); found && flag.Bool && !hasValue {
+					setFlag(flag, "false")
+					continue
+				}
+				found = false
+			}
+			//--------------------
+			if !found {
+				unknownFlag(arg)
+				continue
+			}
+			//------------------------------------------------------------
+			if !hasValue {
+				//--------------------
+				if flag.Bool {
+					value = "true"
+				} else if index+1 < len(args) {
+					index++
+					value = args[index]
+				} else {
+					errs = append(errs, fmt.Errorf("%w: --%s requires a value", ErrCLIFlag, flag.Name))
+					continue
+				}
+				//--------------------
+			}
+			//--------------------
+			setFlag(flag, value)
+			//------------------------------------------------------------
+		case len(arg) > 1 && arg[0] == '-' && !cliIsNumber(arg):
+			//------------------------------------------------------------
+			shorts, value, hasValue := strings.Cut(arg[1:], "=")
+			//------------------------------------------------------------
+			for charIndex := 0; charIndex < len(shorts); charIndex++ {
+				//--------------------
+				flag, found := cliFindFlag(cliArgs.flags, "", shorts[charIndex:charIndex+1])
+				//--------------------
+				if !found {
+					unknownFlag(arg)
+					break
+				}
+				//--------------------
+				lastYesNo := charIndex == len(shorts)-1
+				//--------------------
+				if flag.Bool {
+					if lastYesNo && hasValue {
+						setFlag(flag, value)
+					} else {
+						setFlag(flag, "true")
+					}
+					continue
+				}
+				//--------------------
+				switch {
+				case !lastYesNo:
+					// -p8080
+					setFlag(flag, arg[charIndex+2:])
+				case hasValue:
+					setFlag(flag, value)
+				case index+1 < len(args):
+					index++
+					setFlag(flag, args[index])
+				default:
+					errs = append(errs, fmt.Errorf("%w: -%s requires a value", ErrCLIFlag, flag.Short))
+				}
+				//--------------------
+				break
+				//--------------------
+			}
+			//------------------------------------------------------------
+		default:
+			//------------------------------------------------------------
+			// name=value and bare bool flag names (debug=true / debug)
+			name, value, hasValue := strings.Cut(arg, "=")
+			//--------------------
+			if flag, found := cliFindFlag(cliArgs.flags, name, ""); found && (hasValue || flag.Bool) {
+				//--------------------
+				if !hasValue {
+					value = "true"
+				}
+				//--------------------
+				setFlag(flag, value)
+				//--------------------
+				continue
+				//--------------------
+			}
+			//------------------------------------------------------------
+			if len(cliArgs.Args) == 0 && len(cliArgs.command.Commands) > 0 {
+				//--------------------
+				subcommand, found := cliFindCommand(cliArgs.command.Commands, arg)
+				//--------------------
+				if found {
+					//--------------------
+					cliArgs.Command = append(cliArgs.Command, subcommand.Name)
+					cliArgs.command = subcommand
+					cliArgs.flags = append(cliArgs.flags[:len(cliArgs.flags):len(cliArgs.flags)], subcommand.Flags...)
+					//--------------------
+					allowUnknown = allowUnknown || subcommand.AllowUnknown
+					//--------------------
+					continue
+					//--------------------
+				}
+				//--------------------
+				if cliArgs.command.Run == nil {
+					errs = append(errs, fmt.Errorf("%w: %s", ErrCLICommand, arg))
+					continue
+				}
+				//--------------------
+			}
+			//------------------------------------------------------------
+			cliArgs.Args = append(cliArgs.Args, arg)
+			//------------------------------------------------------------
+		}
+		//------------------------------------------------------------
+	}
+	//------------------------------------------------------------
+	if len(cliArgs.command.Commands) > 0 && cliArgs.command.Run == nil && len(errs) == 0 && !helpYesNo {
+		errs = append(errs, fmt.Errorf("%w: missing command for %s", ErrCLICommand, strings.Join(cliArgs.Command, " ")))
+	}
+	//------------------------------------------------------------
+	for _, flag := range cliArgs.flags {
+		//--------------------
+		if _, found := cliArgs.Sources[flag.Name]; found {
+			continue
+		}
+		//--------------------
+		if envValue := os.Getenv(flag.Env); flag.Env != "" && envValue != "" {
+			//--------------------
+			if flag.Bool {
+				//--------------------
+				boolValue, err := ToBoolE(envValue)
+				//--------------------
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%w: %s=%s", ErrCLIFlag, flag.Env, envValue))
+					continue
+				}
+				//--------------------
+				envValue = strconv.FormatBool(boolValue)
+				//--------------------
+			}
+			//--------------------
+			cliArgs.Values[flag.Name] = envValue
+			cliArgs.Sources[flag.Name] = "env"
+			//--------------------
+		} else if flag.Default != "" {
+			//--------------------
+			cliArgs.Values[flag.Name] = flag.Default
+			cliArgs.Sources[flag.Name] = "default"
+			//--------------------
+		} else if flag.Required {
+			//--------------------
+			errs = append(errs, fmt.Errorf("%w: --%s is required", ErrCLIFlag, flag.Name))
+			//--------------------
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	// Env and Default values are still applied when help is requested
+	if helpYesNo {
+		return cliArgs, ErrCLIHelp
+	}
+	//------------------------------------------------------------
+	return cliArgs, errors.Join(errs...)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CLICommand - Execute => parses args and calls Run of the matched command
+// (help is written to stdout when requested)
+//------------------------------------------------------------
+
+func (command CLICommand) Execute(args []string) error {
+	//------------------------------------------------------------
+	cliArgs, err := ParseCLI(command, args)
+	//------------------------------------------------------------
+	if errors.Is(err, ErrCLIHelp) {
+		fmt.Print(cliArgs.Help())
+		return nil
+	}
+	//--------------------
+	if err != nil {
+		return err
+	}
+	//------------------------------------------------------------
+	if cliArgs.command.Run == nil {
+		return nil
+	}
+	//------------------------------------------------------------
+	return cliArgs.command.Run(cliArgs)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CLICommand - Help
+//------------------------------------------------------------
+
+func (command CLICommand) Help() string {
+	//------------------------------------------------------------
+	return cliHelp(command.Name, command, command.Flags)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// CLIArgs - Help => help text of the matched command (including inherited flags)
+//------------------------------------------------------------
+
+func (cliArgs CLIArgs) Help() string {
+	//------------------------------------------------------------
+	return cliHelp(strings.Join(cliArgs.Command, " "), cliArgs.command, cliArgs.flags)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CLIArgs - IsSet => flag given on the command line or by its Env variable
+//------------------------------------------------------------
+
+func (cliArgs CLIArgs) IsSet(name string) bool {
+	//------------------------------------------------------------
+	source := cliArgs.Sources[name]
+	//------------------------------------------------------------
+	return source == "flag" || source == "env"
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CLIArgs - String
+//------------------------------------------------------------
+
+func (cliArgs CLIArgs) String(name string) string {
+	//------------------------------------------------------------
+	return cliArgs.Values[name]
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CLIArgs - Bool
+//------------------------------------------------------------
+
+func (cliArgs CLIArgs) Bool(name string) bool {
+	//------------------------------------------------------------
+	return ToBool(cliArgs.Values[name])
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CLIArgs - Int
+//------------------------------------------------------------
+
+func (cliArgs CLIArgs) Int(name string) int {
+	//------------------------------------------------------------
+	return ToInt(cliArgs.Values[name])
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// CLIArgs - Duration
+//------------------------------------------------------------
+
+func (cliArgs CLIArgs) Duration(name string) time.Duration {
+	//------------------------------------------------------------
+	return ToDuration(cliArgs.Values[name])
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// cliHelp
+//------------------------------------------------------------
+
+func cliHelp(path string, command CLICommand, flags []CLIFlag) string {
+	//------------------------------------------------------------
+	var builder strings.Builder
+	//------------------------------------------------------------
+	usageLine := "Usage: " + path
+	//--------------------
+	if len(command.Commands) > 0 {
+		usageLine += " <command>"
+	}
+	//--------------------
+	builder.WriteString(usageLine + " [flags] [args]\n")
+	//--------------------
+	if command.Usage != "" {
+		builder.WriteString("\n" + command.Usage + "\n")
+	}
+	//------------------------------------------------------------
+	writer := tabwriter.NewWriter(&builder, 0, 4, 3, ' ', 0)
+	//------------------------------------------------------------
+	if len(command.Commands) > 0 {
+		//--------------------
+		fmt.Fprint(writer, "\nCommands:\n")
+		//--------------------
+		for _, subcommand := range command.Commands {
+			fmt.Fprintf(writer, "  %s\t%s\n", subcommand.Name, subcommand.Usage)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	fmt.Fprint(writer, "\nFlags:\n")
+	//--------------------
+	for _, flag := range flags {
+		//--------------------
+		names := "    --" + flag.Name
+		//--------------------
+		if flag.Short != "" {
+			names = "-" + flag.Short + ", --" + flag.Name
+		}
+		//--------------------
+		if !flag.Bool {
+			names += " <value>"
+		}
+		//--------------------
+		usage := flag.Usage
+		//--------------------
+		if flag.Default != "" {
+			usage += fmt.Sprintf(" (default %s)", flag.Default)
+		}
+		if flag.Env != "" {
+			usage += fmt.Sprintf(" (env %s)", flag.Env)
+		}
+		if flag.Required {
+			usage += " (required)"
+		}
+		//--------------------
+		fmt.Fprintf(writer, "  %s\t%s\n", names, strings.TrimSpace(usage))
+		//--------------------
+	}
+	//--------------------
+	if !cliHasFlag(flags, "help", "h") {
+		fmt.Fprintf(writer, "  %s\t%s\n", "-h, --help", "show this help")
+	}
+	//------------------------------------------------------------
+	writer.Flush()
+	//------------------------------------------------------------
+	return builder.String()
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cliFindFlag => later flags (from subcommands) shadow earlier ones
+//------------------------------------------------------------
+
+func cliFindFlag(flags []CLIFlag, name string, short string) (CLIFlag, bool) {
+	//------------------------------------------------------------
+	for index := len(flags) - 1; index >= 0; index-- {
+		//--------------------
+		flag := flags[index]
+		//--------------------
+		if (name != "" && flag.Name == name) || (short != "" && flag.Short == short) {
+			return flag, true
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return CLIFlag{}, false
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cliHasFlag
+//------------------------------------------------------------
+
+func cliHasFlag(flags []CLIFlag, name string, short string) bool {
+	//------------------------------------------------------------
+	_, found := cliFindFlag(flags, name, short)
+	//------------------------------------------------------------
+	return found
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cliFindCommand
+//------------------------------------------------------------
+
+func cliFindCommand(commands []CLICommand, name string) (CLICommand, bool) {
+	//------------------------------------------------------------
+	for _, command := range commands {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	//------------------------------------------------------------
+	return CLICommand{}, false
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// cliIsNumber => negative numbers are positional arguments
+//------------------------------------------------------------
+
+func cliIsNumber(arg string) bool {
+	//------------------------------------------------------------
+	_, err := strconv.ParseFloat(arg, 64)
+	//------------------------------------------------------------
+	return err == nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
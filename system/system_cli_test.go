@@ -0,0 +1,224 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+
+var cliTestCommand = CLICommand{
+	Name:  "app",
+	Usage: "test application",
+	Flags: []CLIFlag{
+		{Name: "debug", Short: "d", Bool: true, Env: "CLITEST_DEBUG", Usage: "enable debug output"},
+		{Name: "verbose", Short: "v", Bool: true},
+		{Name: "config", Short: "c", Usage: "config file", Default: "app.yaml"},
+	},
+	Commands: []CLICommand{
+		{
+			Name:  "serve",
+			Usage: "start the server",
+			Flags: []CLIFlag{
+				{Name: "port", Short: "p", Env: "CLITEST_PORT", Default: "8080"},
+				{Name: "timeout", Default: "30s"},
+			},
+			Run: func(args CLIArgs) error { return nil },
+		},
+		{
+			Name:  "user",
+			Usage: "manage users",
+			Commands: []CLICommand{
+				{Name: "add", Flags: []CLIFlag{{Name: "name", Required: true}}, Run: func(args CLIArgs) error { return nil }},
+			},
+		},
+	},
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// ParseCLI
+//------------------------------------------------------------
+
+func TestParseCLI(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		args     []string
+		command  string
+		values   string
+		argsList string
+	}{
+		{
+			[]string{"serve", "--debug", "--port", "9000", "file.txt"},
+			"app serve",
+			"config=app.yaml debug=true port=9000 timeout=30s",
+			"file.txt",
+		},
+		{
+			[]string{"serve", "-dv", "-p=9001", "--timeout=5s", "--", "--debug=false"},
+			"app serve",
+			"config=app.yaml debug=true port=9001 timeout=5s verbose=true",
+			"--debug=false",
+		},
+		{
+			[]string{"-c", "other.yaml", "serve", "--no-debug", "-p9002", "-5"},
+			"app serve",
+			"config=other.yaml debug=false port=9002 timeout=30s",
+			"-5",
+		},
+		{
+			[]string{"debug=false", "config=x.yaml", "serve", "verbose"},
+			"app serve",
+			"config=x.yaml debug=false port=8080 timeout=30s verbose=true",
+			"",
+		},
+		{
+			[]string{"user", "add", "--name=tim", "extra"},
+			"app user add",
+			"config=app.yaml name=tim",
+			"extra",
+		},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		cliArgs, err := ParseCLI(cliTestCommand, testCase.args)
+		//--------------------
+		if err != nil {
+			t.Errorf("ParseCLI(%q) err = %v", testCase.args, err)
+			continue
+		}
+		//--------------------
+		if resultString := strings.Join(cliArgs.Command, " "); resultString != testCase.command {
+			t.Errorf("Command = %q but should = %q", resultString, testCase.command)
+		}
+		//--------------------
+		var values []string
+		for _, key := range []string{"config", "debug", "name", "port", "timeout", "verbose"} {
+			if value, found := cliArgs.Values[key]; found {
+				values = append(values, key+"="+value)
+			}
+		}
+		//--------------------
+		if resultString := strings.Join(values, " "); resultString != testCase.values {
+			t.Errorf("%q: Values = %q but should = %q", testCase.args, resultString, testCase.values)
+		}
+		//--------------------
+		if resultString := strings.Join(cliArgs.Args, " "); resultString != testCase.argsList {
+			t.Errorf("%q: Args = %q but should = %q", testCase.args, resultString, testCase.argsList)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ParseCLI environment fallback
+//------------------------------------------------------------
+
+func TestParseCLIEnv(t *testing.T) {
+	//------------------------------------------------------------
+	t.Setenv("CLITEST_DEBUG", "yes")
+	t.Setenv("CLITEST_PORT", "7000")
+	//------------------------------------------------------------
+	cliArgs, err := ParseCLI(cliTestCommand, []string{"serve"})
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	if !cliArgs.Bool("debug") || !cliArgs.IsSet("debug") || cliArgs.Sources["debug"] != "env" {
+		t.Errorf("debug = %v (%s) but should = true (env)", cliArgs.Bool("debug"), cliArgs.Sources["debug"])
+	}
+	//--------------------
+	if cliArgs.Int("port") != 7000 || cliArgs.Duration("timeout") != 30*time.Second || cliArgs.IsSet("timeout") {
+		t.Errorf("port = %d, timeout = %v", cliArgs.Int("port"), cliArgs.Duration("timeout"))
+	}
+	//------------------------------------------------------------
+	// the command line takes precedence over the environment
+	cliArgs, _ = ParseCLI(cliTestCommand, []string{"serve", "--port", "7001"})
+	//--------------------
+	if cliArgs.String("port") != "7001" {
+		t.Errorf("port = %q but should = %q", cliArgs.String("port"), "7001")
+	}
+	//------------------------------------------------------------
+	// Env and Default values are applied before help is returned
+	cliArgs, err = ParseCLI(cliTestCommand, []string{"serve", "--help"})
+	//--------------------
+	if !errors.Is(err, ErrCLIHelp) || !cliArgs.Bool("debug") || cliArgs.String("port") != "7000" || cliArgs.String("timeout") != "30s" {
+		t.Errorf("debug = %v, port = %q, timeout = %q, err = %v", cliArgs.Bool("debug"), cliArgs.String("port"), cliArgs.String("timeout"), err)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ParseCLI errors
+//------------------------------------------------------------
+
+func TestParseCLIErrors(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		args        []string
+		expectedErr error
+	}{
+		{[]string{"serve", "--unknown"}, ErrCLIFlag},
+		{[]string{"serve", "-x"}, ErrCLIFlag},
+		{[]string{"serve", "--port"}, ErrCLIFlag},
+		{[]string{"serve", "--debug=maybe"}, ErrCLIFlag},
+		{[]string{"user", "add"}, ErrCLIFlag},
+		{[]string{"user"}, ErrCLICommand},
+		{[]string{"unknown"}, ErrCLICommand},
+		{[]string{"serve", "--help"}, ErrCLIHelp},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		if _, err := ParseCLI(cliTestCommand, testCase.args); !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("ParseCLI(%q) err = %v but should = %v", testCase.args, err, testCase.expectedErr)
+		}
+	}
+	//------------------------------------------------------------
+	lenientCommand := CLICommand{Name: "lenient", Flags: cliTestCommand.Flags, AllowUnknown: true}
+	//--------------------
+	cliArgs, err := ParseCLI(lenientCommand, []string{"-test.v=true", "--debug", "-x"})
+	//--------------------
+	if err != nil || strings.Join(cliArgs.Args, " ") != "-test.v=true -x" || !cliArgs.Bool("debug") {
+		t.Errorf("Args = %q, err = %v", cliArgs.Args, err)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Help
+//------------------------------------------------------------
+
+func TestCLIHelp(t *testing.T) {
+	//------------------------------------------------------------
+	helpText := cliTestCommand.Help()
+	//--------------------
+	for _, expected := range []string{"Usage: app <command> [flags] [args]", "serve", "start the server", "-d, --debug", "(env CLITEST_DEBUG)", "-c, --config <value>", "(default app.yaml)", "-h, --help"} {
+		if !strings.Contains(helpText, expected) {
+			t.Errorf("help text does not contain %q:\n%s", expected, helpText)
+		}
+	}
+	//------------------------------------------------------------
+	cliArgs, _ := ParseCLI(cliTestCommand, []string{"serve", "-h"})
+	helpText = cliArgs.Help()
+	//--------------------
+	for _, expected := range []string{"Usage: app serve [flags] [args]", "--debug", "-p, --port <value>"} {
+		if !strings.Contains(helpText, expected) {
+			t.Errorf("help text does not contain %q:\n%s", expected, helpText)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
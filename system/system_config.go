@@ -0,0 +1,233 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/timbrockley/golang-main/file"
+)
+
+//------------------------------------------------------------
+
+var ErrConfigRequired = errors.New("required environment variable not set")
+
+//------------------------------------------------------------
+// ConfigOptions
+//------------------------------------------------------------
+
+type ConfigOptions struct {
+	// prepended to every variable name (APP_ => APP_PORT)
+	Prefix string
+	// .env file or directory searched with FindENVFilename
+	// (defaults to the directory of the calling source file)
+	FilePath string
+	// do not load a .env file (only use the current environment)
+	SkipFile bool
+	// separator used to split slice values (defaults to ",")
+	Separator string
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// LoadConfig => fills the struct pointed to by target from environment variables
+//------------------------------------------------------------
+// fields are read using `env:"NAME"` or `env:"NAME,required"` tags and
+// `default:"value"` is used when the variable is not set (or is blank),
+// nested structs add their env tag (if any) plus "_" to the prefix,
+// variables already set in the environment take precedence over the .env file
+//------------------------------------------------------------
+
+func LoadConfig(target any, options ...ConfigOptions) error {
+	//------------------------------------------------------------
+	var configOptions ConfigOptions
+	//--------------------
+	if len(options) > 0 {
+		configOptions = options[0]
+	}
+	//--------------------
+	if configOptions.Separator == "" {
+		configOptions.Separator = ","
+	}
+	//------------------------------------------------------------
+	reflectValue := reflect.ValueOf(target)
+	//--------------------
+	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w to a struct (got %T)", ErrBindTarget, target)
+	}
+	//------------------------------------------------------------
+	if !configOptions.SkipFile {
+		//--------------------
+		filePath := configOptions.FilePath
+		//--------------------
+		if filePath == "" {
+			// runtime.Caller(1) => calling script
+			_, filePath, _, _ = runtime.Caller(1)
+			filePath = file.Path(filePath)
+		}
+		//--------------------
+		if err := loadConfigFile(filePath, configOptions.FilePath != ""); err != nil {
+			return err
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	var fieldErrors []BindFieldError
+	//------------------------------------------------------------
+	loadConfigStruct(reflectValue.Elem(), configOptions.Prefix, "", configOptions, &fieldErrors)
+	//------------------------------------------------------------
+	if len(fieldErrors) > 0 {
+		return BindError{Fields: fieldErrors}
+	}
+	//------------------------------------------------------------
+	return nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// loadConfigFile => a missing .env file is only an error when the path was given
+//------------------------------------------------------------
+
+func loadConfigFile(filePath string, explicitPath bool) error {
+	//------------------------------------------------------------
+	if IsDirectory, _ := file.IsDirectory(filePath); IsDirectory {
+		filePath = file.FilePathJoin(filePath, FindENVFilename(filePath))
+	}
+	//------------------------------------------------------------
+	if !file.FilePathExists(filePath) {
+		//--------------------
+		if explicitPath {
+			return fmt.Errorf("env file not found: %s", filePath)
+		}
+		//--------------------
+		return nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return godotenv.Load(filePath)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// loadConfigStruct
+//------------------------------------------------------------
+
+func loadConfigStruct(structValue reflect.Value, prefix string, path string, configOptions ConfigOptions, fieldErrors *[]BindFieldError) {
+	//------------------------------------------------------------
+	structType := structValue.Type()
+	//------------------------------------------------------------
+	for index := 0; index < structType.NumField(); index++ {
+		//------------------------------------------------------------
+		field := structType.Field(index)
+		fieldValue := structValue.Field(index)
+		//------------------------------------------------------------
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		//------------------------------------------------------------
+		tag, tagged := field.Tag.Lookup("env")
+		name, flags, _ := strings.Cut(tag, ",")
+		//--------------------
+		if name == "-" {
+			continue
+		}
+		//------------------------------------------------------------
+		if isConfigStruct(field.Type) {
+			//--------------------
+			nestedPrefix := prefix
+			//--------------------
+			if name != "" {
+				nestedPrefix += name + "_"
+			}
+			//--------------------
+			loadConfigStruct(fieldValue, nestedPrefix, path+field.Name+".", configOptions, fieldErrors)
+			//--------------------
+			continue
+		}
+		//------------------------------------------------------------
+		if !tagged || name == "" || !field.IsExported() {
+			continue
+		}
+		//------------------------------------------------------------
+		envName := prefix + name
+		//--------------------
+		value, found := os.LookupEnv(envName)
+		//------------------------------------------------------------
+		if !found || value == "" {
+			//--------------------
+			defaultValue, hasDefault := field.Tag.Lookup("default")
+			//--------------------
+			switch {
+			case hasDefault:
+				value = defaultValue
+			case strings.Contains(","+flags+",", ",required,"):
+				*fieldErrors = append(*fieldErrors, BindFieldError{Field: path + field.Name, Key: envName, Err: ErrConfigRequired})
+				continue
+			default:
+				// keep any value already in the struct
+				continue
+			}
+			//--------------------
+		}
+		//------------------------------------------------------------
+		var fieldData any = value
+		//--------------------
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8 {
+			//--------------------
+			values := []string{}
+			//--------------------
+			for _, item := range strings.Split(value, configOptions.Separator) {
+				if item = strings.TrimSpace(item); item != "" {
+					values = append(values, item)
+				}
+			}
+			//--------------------
+			fieldData = values
+			//--------------------
+		}
+		//------------------------------------------------------------
+		bindValue(fieldValue, fieldData, nil, path+field.Name, envName, fieldErrors)
+		//------------------------------------------------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// isConfigStruct => structs that are loaded field by field
+// (time.Time and text unmarshalers are read from a single variable)
+//------------------------------------------------------------
+
+func isConfigStruct(fieldType reflect.Type) bool {
+	//------------------------------------------------------------
+	if fieldType.Kind() != reflect.Struct || fieldType == timeType {
+		return false
+	}
+	//------------------------------------------------------------
+	return !reflect.PointerTo(fieldType).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
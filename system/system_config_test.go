@@ -0,0 +1,152 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+
+type configTestDB struct {
+	Host string `env:"HOST" default:"localhost"`
+	Port int    `env:"PORT,required"`
+}
+
+type configTestConfig struct {
+	Name     string        `env:"NAME,required"`
+	Debug    bool          `env:"DEBUG"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"30s"`
+	Hosts    []string      `env:"HOSTS"`
+	Ports    []uint16      `env:"PORTS"`
+	Ratio    *float64      `env:"RATIO"`
+	ID       UUID          `env:"ID"`
+	DB       configTestDB  `env:"DB"`
+	Cache    configTestDB
+	Kept     string `env:"KEPT"`
+	Ignored  string `env:"-"`
+	Untagged string
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// LoadConfig
+//------------------------------------------------------------
+
+func TestLoadConfig(t *testing.T) {
+	//------------------------------------------------------------
+	tempPath := t.TempDir()
+	//--------------------
+	envFileData := "CFGTEST_NAME=from-file\nCFGTEST_DEBUG=true\nCFGTEST_DB_PORT=5432\n"
+	//--------------------
+	if err := os.WriteFile(filepath.Join(tempPath, ".env"), []byte(envFileData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	uuid, _ := NewUUIDv7()
+	//--------------------
+	t.Setenv("CFGTEST_NAME", "from-env")
+	t.Setenv("CFGTEST_HOSTS", "a.example.com, b.example.com,")
+	t.Setenv("CFGTEST_PORTS", "80,443")
+	t.Setenv("CFGTEST_RATIO", "0.75")
+	t.Setenv("CFGTEST_ID", uuid.String())
+	t.Setenv("CFGTEST_PORT", "6379")
+	t.Setenv("CFGTEST_KEPT", "")
+	t.Setenv("CFGTEST_UNTAGGED", "x")
+	//------------------------------------------------------------
+	// variables loaded from the .env file are removed after the test
+	t.Setenv("CFGTEST_DEBUG", "")
+	t.Setenv("CFGTEST_DB_PORT", "")
+	os.Unsetenv("CFGTEST_DEBUG")
+	os.Unsetenv("CFGTEST_DB_PORT")
+	//------------------------------------------------------------
+	config := configTestConfig{Kept: "preset"}
+	//--------------------
+	if err := LoadConfig(&config, ConfigOptions{Prefix: "CFGTEST_", FilePath: tempPath}); err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	if config.Name != "from-env" || !config.Debug || config.Timeout != 30*time.Second {
+		t.Errorf("Name = %q, Debug = %v, Timeout = %v", config.Name, config.Debug, config.Timeout)
+	}
+	//--------------------
+	if len(config.Hosts) != 2 || config.Hosts[1] != "b.example.com" || len(config.Ports) != 2 || config.Ports[1] != 443 {
+		t.Errorf("Hosts = %q, Ports = %v", config.Hosts, config.Ports)
+	}
+	//--------------------
+	if config.Ratio == nil || *config.Ratio != 0.75 || config.ID != uuid {
+		t.Errorf("Ratio = %v, ID = %s", config.Ratio, config.ID)
+	}
+	//--------------------
+	if config.DB.Host != "localhost" || config.DB.Port != 5432 || config.Cache.Port != 6379 {
+		t.Errorf("DB = %+v, Cache = %+v", config.DB, config.Cache)
+	}
+	//--------------------
+	if config.Kept != "preset" || config.Ignored != "" || config.Untagged != "" {
+		t.Errorf("Kept = %q, Ignored = %q, Untagged = %q", config.Kept, config.Ignored, config.Untagged)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// LoadConfig errors
+//------------------------------------------------------------
+
+func TestLoadConfigErrors(t *testing.T) {
+	//------------------------------------------------------------
+	t.Setenv("CFGERR_DEBUG", "maybe")
+	t.Setenv("CFGERR_PORTS", "80,99999")
+	t.Setenv("CFGERR_PORT", "6379")
+	//------------------------------------------------------------
+	var config configTestConfig
+	//--------------------
+	err := LoadConfig(&config, ConfigOptions{Prefix: "CFGERR_", SkipFile: true})
+	//------------------------------------------------------------
+	var bindError BindError
+	//--------------------
+	if !errors.As(err, &bindError) {
+		t.Fatalf("err = %v but should be a BindError", err)
+	}
+	//------------------------------------------------------------
+	expectedKeys := []string{"CFGERR_NAME", "CFGERR_DEBUG", "CFGERR_PORTS", "CFGERR_DB_PORT"}
+	//--------------------
+	failedKeys := map[string]bool{}
+	//--------------------
+	for _, fieldError := range bindError.Fields {
+		failedKeys[fieldError.Key] = true
+	}
+	//--------------------
+	for _, key := range expectedKeys {
+		if !failedKeys[key] {
+			t.Errorf("%s should have failed (%v)", key, err)
+		}
+	}
+	//--------------------
+	if len(bindError.Fields) != len(expectedKeys) {
+		t.Errorf("len(Fields) = %d but should = %d (%v)", len(bindError.Fields), len(expectedKeys), err)
+	}
+	//--------------------
+	if !errors.Is(err, ErrConfigRequired) || !errors.Is(err, ErrConvertOverflow) {
+		t.Errorf("err = %v should wrap %v and %v", err, ErrConfigRequired, ErrConvertOverflow)
+	}
+	//------------------------------------------------------------
+	if err := LoadConfig(&config, ConfigOptions{FilePath: filepath.Join(t.TempDir(), "missing.env")}); err == nil {
+		t.Error("a missing FilePath should return an error")
+	}
+	//--------------------
+	if err := LoadConfig(config); !errors.Is(err, ErrBindTarget) {
+		t.Errorf("err = %v but should = %v", err, ErrBindTarget)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,531 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------
+
+var (
+	ErrConvert         = errors.New("invalid value")
+	ErrConvertOverflow = errors.New("value out of range")
+)
+
+//------------------------------------------------------------
+
+// Convertible => target types supported by Convert
+type Convertible interface {
+	string | bool |
+		int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64 |
+		time.Duration | time.Time
+}
+
+//------------------------------------------------------------
+
+const unixMillisThreshold = 1e11
+
+//------------------------------------------------------------
+
+// layouts tried in order by ToTimeE
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999",
+	time.DateOnly,
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// Convert => converts value to T, returning an error instead of a zero value
+//------------------------------------------------------------
+
+func Convert[T Convertible](value any) (T, error) {
+	//------------------------------------------------------------
+	var result T
+	var converted any
+	var err error
+	//------------------------------------------------------------
+	switch any(result).(type) {
+	case string:
+		converted, err = ToStringE(value)
+	case bool:
+		converted, err = ToBoolE(value)
+	case int:
+		converted, err = ToIntE(value)
+	case int8:
+		converted, err = convertSigned[int8](value, 8)
+	case int16:
+		converted, err = convertSigned[int16](value, 16)
+	case int32:
+		converted, err = ToInt32E(value)
+	case int64:
+		converted, err = ToInt64E(value)
+	case uint:
+		converted, err = convertUnsigned[uint](value, strconv.IntSize)
+	case uint8:
+		converted, err = convertUnsigned[uint8](value, 8)
+	case uint16:
+		converted, err = convertUnsigned[uint16](value, 16)
+	case uint32:
+		converted, err = convertUnsigned[uint32](value, 32)
+	case uint64:
+		converted, err = convertUnsigned[uint64](value, 64)
+	case float32:
+		converted, err = ToFloat32E(value)
+	case float64:
+		converted, err = ToFloat64E(value)
+	case time.Duration:
+		converted, err = ToDurationE(value)
+	case time.Time:
+		converted, err = ToTimeE(value)
+	}
+	//------------------------------------------------------------
+	if err != nil {
+		return result, err
+	}
+	//------------------------------------------------------------
+	return converted.(T), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToStringE
+//------------------------------------------------------------
+
+func ToStringE(value any) (string, error) {
+	//------------------------------------------------------------
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, nil
+	case []byte:
+		return string(typedValue), nil
+	case fmt.Stringer:
+		return typedValue.String(), nil
+	}
+	//------------------------------------------------------------
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return fmt.Sprint(value), nil
+	}
+	//------------------------------------------------------------
+	return "", convertError(value, "string", ErrConvert)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToIntE
+//------------------------------------------------------------
+
+func ToIntE(value any) (int, error) {
+	//------------------------------------------------------------
+	return convertSigned[int](value, strconv.IntSize)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToInt32E
+//------------------------------------------------------------
+
+func ToInt32E(value any) (int32, error) {
+	//------------------------------------------------------------
+	return convertSigned[int32](value, 32)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToInt64E
+//------------------------------------------------------------
+
+func ToInt64E(value any) (int64, error) {
+	//------------------------------------------------------------
+	return convertSigned[int64](value, 64)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToFloat32E
+//------------------------------------------------------------
+
+func ToFloat32E(value any) (float32, error) {
+	//------------------------------------------------------------
+	float64Val, err := convertFloat(value, "float32")
+	if err != nil {
+		return 0, err
+	}
+	//------------------------------------------------------------
+	if math.Abs(float64Val) > math.MaxFloat32 && !math.IsInf(float64Val, 0) {
+		return 0, convertError(value, "float32", ErrConvertOverflow)
+	}
+	//------------------------------------------------------------
+	return float32(float64Val), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToFloat64E
+//------------------------------------------------------------
+
+func ToFloat64E(value any) (float64, error) {
+	//------------------------------------------------------------
+	return convertFloat(value, "float64")
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToBoolE => accepts true/false, yes/no, on/off, 1/0 (case-insensitive)
+//------------------------------------------------------------
+
+func ToBoolE(value any) (bool, error) {
+	//------------------------------------------------------------
+	if typedValue, ok := value.([]byte); ok {
+		value = string(typedValue)
+	}
+	//------------------------------------------------------------
+	reflectValue := reflect.ValueOf(value)
+	//------------------------------------------------------------
+	switch reflectValue.Kind() {
+	case reflect.Bool:
+		return reflectValue.Bool(), nil
+	case reflect.String:
+		switch strings.ToLower(strings.TrimSpace(reflectValue.String())) {
+		case "1", "t", "true", "y", "yes", "on":
+			return true, nil
+		case "0", "f", "false", "n", "no", "off":
+			return false, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if float64Val, err := convertFloat(value, "bool"); err == nil && (float64Val == 0 || float64Val == 1) {
+			return float64Val == 1, nil
+		}
+	}
+	//------------------------------------------------------------
+	return false, convertError(value, "bool", ErrConvert)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToDurationE => strings use time.ParseDuration ("1h30m"), plain numbers are seconds
+//------------------------------------------------------------
+
+func ToDurationE(value any) (time.Duration, error) {
+	//------------------------------------------------------------
+	if typedValue, ok := value.(time.Duration); ok {
+		return typedValue, nil
+	}
+	//------------------------------------------------------------
+	if typedValue, ok := value.([]byte); ok {
+		value = string(typedValue)
+	}
+	//------------------------------------------------------------
+	if reflect.ValueOf(value).Kind() == reflect.String {
+		//--------------------
+		durationString := strings.TrimSpace(reflect.ValueOf(value).String())
+		//--------------------
+		if duration, err := time.ParseDuration(durationString); err == nil {
+			return duration, nil
+		}
+		//--------------------
+		if _, err := strconv.ParseFloat(durationString, 64); err != nil {
+			return 0, convertError(value, "time.Duration", ErrConvert)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	seconds, err := convertFloat(value, "time.Duration")
+	if err != nil {
+		return 0, err
+	}
+	//------------------------------------------------------------
+	if math.IsNaN(seconds) || math.Abs(seconds) >= math.MaxInt64/float64(time.Second) {
+		return 0, convertError(value, "time.Duration", ErrConvertOverflow)
+	}
+	//------------------------------------------------------------
+	return time.Duration(seconds * float64(time.Second)), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToTimeE => strings are tried against RFC 3339 and common MySQL/Postgres layouts,
+// numbers are unix seconds (or milliseconds when too large to be seconds)
+//------------------------------------------------------------
+
+func ToTimeE(value any) (time.Time, error) {
+	//------------------------------------------------------------
+	switch typedValue := value.(type) {
+	case time.Time:
+		return typedValue, nil
+	case *time.Time:
+		if typedValue != nil {
+			return *typedValue, nil
+		}
+	case []byte:
+		return ToTimeE(string(typedValue))
+	case string:
+		//--------------------
+		timeString := strings.TrimSpace(typedValue)
+		//--------------------
+		for _, layout := range timeLayouts {
+			if timeVal, err := time.Parse(layout, timeString); err == nil {
+				return timeVal, nil
+			}
+		}
+		//--------------------
+		if int64Val, err := strconv.ParseInt(timeString, 10, 64); err == nil {
+			return unixTime(int64Val), nil
+		}
+		//--------------------
+	default:
+		if int64Val, err := convertSigned[int64](value, 64); err == nil {
+			return unixTime(int64Val), nil
+		}
+	}
+	//------------------------------------------------------------
+	return time.Time{}, convertError(value, "time.Time", ErrConvert)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// convertError
+//------------------------------------------------------------
+
+func convertError(value any, typeName string, err error) error {
+	//------------------------------------------------------------
+	return fmt.Errorf("cannot convert %T(%v) to %s: %w", value, value, typeName, err)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// unixTime => values beyond the year 5138 in seconds are treated as milliseconds
+//------------------------------------------------------------
+
+func unixTime(timestamp int64) time.Time {
+	//------------------------------------------------------------
+	if timestamp > unixMillisThreshold || timestamp < -unixMillisThreshold {
+		return time.UnixMilli(timestamp)
+	}
+	//------------------------------------------------------------
+	return time.Unix(timestamp, 0)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// convertSigned => converts value to a signed integer of the given bit size
+//------------------------------------------------------------
+
+func convertSigned[T int | int8 | int16 | int32 | int64](value any, bits int) (T, error) {
+	//------------------------------------------------------------
+	var int64Val int64
+	//------------------------------------------------------------
+	typeName := fmt.Sprintf("%T", T(0))
+	//------------------------------------------------------------
+	if typedValue, ok := value.([]byte); ok {
+		value = string(typedValue)
+	}
+	//------------------------------------------------------------
+	reflectValue := reflect.ValueOf(value)
+	//------------------------------------------------------------
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		int64Val = reflectValue.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if reflectValue.Uint() > math.MaxInt64 {
+			return 0, convertError(value, typeName, ErrConvertOverflow)
+		}
+		int64Val = int64(reflectValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		float64Val := reflectValue.Float()
+		if float64Val != math.Trunc(float64Val) {
+			return 0, convertError(value, typeName, ErrConvert)
+		}
+		// float64(math.MaxInt64) rounds up to 2^63 so the upper bound is exclusive
+		if float64Val < math.MinInt64 || float64Val >= math.MaxInt64 {
+			return 0, convertError(value, typeName, ErrConvertOverflow)
+		}
+		int64Val = int64(float64Val)
+	case reflect.Bool:
+		if reflectValue.Bool() {
+			int64Val = 1
+		}
+	case reflect.String:
+		//--------------------
+		var err error
+		//--------------------
+		stringVal := strings.TrimSpace(reflectValue.String())
+		//--------------------
+		int64Val, err = strconv.ParseInt(stringVal, 0, 64)
+		//--------------------
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, convertError(value, typeName, ErrConvertOverflow)
+		}
+		//--------------------
+		if err != nil {
+			// allow whole numbers written as floats ("1.0", "1e3")
+			float64Val, floatErr := strconv.ParseFloat(stringVal, 64)
+			if floatErr != nil {
+				return 0, convertError(value, typeName, ErrConvert)
+			}
+			return convertSigned[T](float64Val, bits)
+		}
+		//--------------------
+	default:
+		return 0, convertError(value, typeName, ErrConvert)
+	}
+	//------------------------------------------------------------
+	if bits < 64 && (int64Val < -1<<(bits-1) || int64Val > 1<<(bits-1)-1) {
+		return 0, convertError(value, typeName, ErrConvertOverflow)
+	}
+	//------------------------------------------------------------
+	return T(int64Val), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// convertUnsigned => converts value to an unsigned integer of the given bit size
+//------------------------------------------------------------
+
+func convertUnsigned[T uint | uint8 | uint16 | uint32 | uint64](value any, bits int) (T, error) {
+	//------------------------------------------------------------
+	var uint64Val uint64
+	//------------------------------------------------------------
+	typeName := fmt.Sprintf("%T", T(0))
+	//------------------------------------------------------------
+	if typedValue, ok := value.([]byte); ok {
+		value = string(typedValue)
+	}
+	//------------------------------------------------------------
+	reflectValue := reflect.ValueOf(value)
+	//------------------------------------------------------------
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if reflectValue.Int() < 0 {
+			return 0, convertError(value, typeName, ErrConvertOverflow)
+		}
+		uint64Val = uint64(reflectValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		uint64Val = reflectValue.Uint()
+	case reflect.Float32, reflect.Float64:
+		float64Val := reflectValue.Float()
+		if float64Val != math.Trunc(float64Val) {
+			return 0, convertError(value, typeName, ErrConvert)
+		}
+		// float64(math.MaxUint64) rounds up to 2^64 so the upper bound is exclusive
+		if float64Val < 0 || float64Val >= math.MaxUint64 {
+			return 0, convertError(value, typeName, ErrConvertOverflow)
+		}
+		uint64Val = uint64(float64Val)
+	case reflect.Bool:
+		if reflectValue.Bool() {
+			uint64Val = 1
+		}
+	case reflect.String:
+		//--------------------
+		var err error
+		//--------------------
+		stringVal := strings.TrimSpace(reflectValue.String())
+		//--------------------
+		uint64Val, err = strconv.ParseUint(stringVal, 0, 64)
+		//--------------------
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, convertError(value, typeName, ErrConvertOverflow)
+		}
+		//--------------------
+		if err != nil {
+			float64Val, floatErr := strconv.ParseFloat(stringVal, 64)
+			if floatErr != nil {
+				return 0, convertError(value, typeName, ErrConvert)
+			}
+			return convertUnsigned[T](float64Val, bits)
+		}
+		//--------------------
+	default:
+		return 0, convertError(value, typeName, ErrConvert)
+	}
+	//------------------------------------------------------------
+	if bits < 64 && uint64Val > 1<<bits-1 {
+		return 0, convertError(value, typeName, ErrConvertOverflow)
+	}
+	//------------------------------------------------------------
+	return T(uint64Val), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// convertFloat
+//------------------------------------------------------------
+
+func convertFloat(value any, typeName string) (float64, error) {
+	//------------------------------------------------------------
+	if typedValue, ok := value.([]byte); ok {
+		value = string(typedValue)
+	}
+	//------------------------------------------------------------
+	reflectValue := reflect.ValueOf(value)
+	//------------------------------------------------------------
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflectValue.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(reflectValue.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float(), nil
+	case reflect.Bool:
+		if reflectValue.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.String:
+		//--------------------
+		float64Val, err := strconv.ParseFloat(strings.TrimSpace(reflectValue.String()), 64)
+		//--------------------
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, convertError(value, typeName, ErrConvertOverflow)
+		}
+		//--------------------
+		if err != nil {
+			return 0, convertError(value, typeName, ErrConvert)
+		}
+		//--------------------
+		return float64Val, nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return 0, convertError(value, typeName, ErrConvert)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,325 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// ToIntE / ToInt32E / ToInt64E
+//------------------------------------------------------------
+
+func TestToIntE(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		value       any
+		expected    int64
+		expectedErr error
+	}{
+		{42, 42, nil},
+		{int8(-8), -8, nil},
+		{uint64(7), 7, nil},
+		{3.0, 3, nil},
+		{true, 1, nil},
+		{" 123 ", 123, nil},
+		{"-0x10", -16, nil},
+		{"1e3", 1000, nil},
+		{[]byte("99"), 99, nil},
+		{json.Number("12"), 12, nil},
+		{time.Second, 1e9, nil},
+		{"", 0, ErrConvert},
+		{"abc", 0, ErrConvert},
+		{"1.5", 0, ErrConvert},
+		{1.5, 0, ErrConvert},
+		{nil, 0, ErrConvert},
+		{[]int{1}, 0, ErrConvert},
+		{uint64(math.MaxUint64), 0, ErrConvertOverflow},
+		{"9223372036854775808", 0, ErrConvertOverflow},
+		{1e19, 0, ErrConvertOverflow},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		result, err := ToInt64E(testCase.value)
+		//--------------------
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("(%#v) err = %v but should = %v", testCase.value, err, testCase.expectedErr)
+		} else if result != testCase.expected {
+			t.Errorf("(%#v) result = %d but should = %d", testCase.value, result, testCase.expected)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if result, err := ToIntE("-12"); err != nil || result != -12 {
+		t.Errorf("ToIntE(\"-12\") = %d, %v but should = %d", result, err, -12)
+	}
+	//------------------------------------------------------------
+	int32Cases := []struct {
+		value       any
+		expected    int32
+		expectedErr error
+	}{
+		{math.MaxInt32, math.MaxInt32, nil},
+		{math.MinInt32, math.MinInt32, nil},
+		{int64(math.MaxInt32 + 1), 0, ErrConvertOverflow},
+		{int64(math.MinInt32 - 1), 0, ErrConvertOverflow},
+		{"3000000000", 0, ErrConvertOverflow},
+		{float64(1 << 40), 0, ErrConvertOverflow},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range int32Cases {
+		//--------------------
+		result, err := ToInt32E(testCase.value)
+		//--------------------
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("(%#v) err = %v but should = %v", testCase.value, err, testCase.expectedErr)
+		} else if result != testCase.expected {
+			t.Errorf("(%#v) result = %d but should = %d", testCase.value, result, testCase.expected)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToFloat64E / ToFloat32E
+//------------------------------------------------------------
+
+func TestToFloat64E(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		value       any
+		expected    float64
+		expectedErr error
+	}{
+		{1.5, 1.5, nil},
+		{float32(0.5), 0.5, nil},
+		{-3, -3, nil},
+		{uint8(255), 255, nil},
+		{"2.5e2", 250, nil},
+		{" -0.25 ", -0.25, nil},
+		{false, 0, nil},
+		{"1.5x", 0, ErrConvert},
+		{"1e400", 0, ErrConvertOverflow},
+		{nil, 0, ErrConvert},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		result, err := ToFloat64E(testCase.value)
+		//--------------------
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("(%#v) err = %v but should = %v", testCase.value, err, testCase.expectedErr)
+		} else if result != testCase.expected {
+			t.Errorf("(%#v) result = %v but should = %v", testCase.value, result, testCase.expected)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if _, err := ToFloat32E(1e39); !errors.Is(err, ErrConvertOverflow) {
+		t.Errorf("err = %v but should = %v", err, ErrConvertOverflow)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToBoolE
+//------------------------------------------------------------
+
+func TestToBoolE(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		value       any
+		expected    bool
+		expectedErr error
+	}{
+		{true, true, nil},
+		{"TRUE", true, nil},
+		{"yes", true, nil},
+		{"On", true, nil},
+		{"1", true, nil},
+		{1, true, nil},
+		{"false", false, nil},
+		{"no", false, nil},
+		{"off", false, nil},
+		{[]byte("0"), false, nil},
+		{0.0, false, nil},
+		{"", false, ErrConvert},
+		{"maybe", false, ErrConvert},
+		{2, false, ErrConvert},
+		{nil, false, ErrConvert},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		result, err := ToBoolE(testCase.value)
+		//--------------------
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("(%#v) err = %v but should = %v", testCase.value, err, testCase.expectedErr)
+		} else if result != testCase.expected {
+			t.Errorf("(%#v) result = %v but should = %v", testCase.value, result, testCase.expected)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToDurationE
+//------------------------------------------------------------
+
+func TestToDurationE(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		value       any
+		expected    time.Duration
+		expectedErr error
+	}{
+		{"1h30m", 90 * time.Minute, nil},
+		{" 250ms ", 250 * time.Millisecond, nil},
+		{[]byte("2s"), 2 * time.Second, nil},
+		{time.Minute, time.Minute, nil},
+		{300, 5 * time.Minute, nil},
+		{"30", 30 * time.Second, nil},
+		{"0.5", 500 * time.Millisecond, nil},
+		{1.5, 1500 * time.Millisecond, nil},
+		{"soon", 0, ErrConvert},
+		{nil, 0, ErrConvert},
+		{uint64(math.MaxUint64), 0, ErrConvertOverflow},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		result, err := ToDurationE(testCase.value)
+		//--------------------
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("(%#v) err = %v but should = %v", testCase.value, err, testCase.expectedErr)
+		} else if result != testCase.expected {
+			t.Errorf("(%#v) result = %v but should = %v", testCase.value, result, testCase.expected)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToTimeE
+//------------------------------------------------------------
+
+func TestToTimeE(t *testing.T) {
+	//------------------------------------------------------------
+	expectedTime := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	//------------------------------------------------------------
+	testCases := []struct {
+		value    any
+		expected time.Time
+	}{
+		{expectedTime, expectedTime},
+		{&expectedTime, expectedTime},
+		{"2024-03-01T12:30:45Z", expectedTime},
+		{"2024-03-01T13:30:45+01:00", expectedTime},
+		{"2024-03-01T12:30:45", expectedTime},
+		{"2024-03-01 12:30:45", expectedTime},
+		{[]byte("2024-03-01 12:30:45.000"), expectedTime},
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Fri, 01 Mar 2024 12:30:45 +0000", expectedTime},
+		{"2024-03-01 13:30:45+01", expectedTime},
+		{"2024-03-01 12:30:45.000000+00", expectedTime},
+		{expectedTime.Unix(), expectedTime},
+		{"1709296245", expectedTime},
+		{expectedTime.UnixMilli(), expectedTime},
+		{"1709296245000", expectedTime},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		result, err := ToTimeE(testCase.value)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%#v) %v", testCase.value, err)
+		} else if !result.Equal(testCase.expected) {
+			t.Errorf("(%#v) result = %v but should = %v", testCase.value, result, testCase.expected)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	for _, value := range []any{"", "yesterday", "2024-13-01", nil, (*time.Time)(nil), 1.5} {
+		if _, err := ToTimeE(value); !errors.Is(err, ErrConvert) {
+			t.Errorf("(%#v) err = %v but should = %v", value, err, ErrConvert)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// Convert
+//------------------------------------------------------------
+
+func TestConvert(t *testing.T) {
+	//------------------------------------------------------------
+	if result, err := Convert[int32]("2147483647"); err != nil || result != math.MaxInt32 {
+		t.Errorf("Convert[int32] = %d, %v but should = %d", result, err, math.MaxInt32)
+	}
+	//--------------------
+	if _, err := Convert[int32]("2147483648"); !errors.Is(err, ErrConvertOverflow) {
+		t.Errorf("err = %v but should = %v", err, ErrConvertOverflow)
+	}
+	//--------------------
+	if _, err := Convert[int8](128); !errors.Is(err, ErrConvertOverflow) {
+		t.Errorf("err = %v but should = %v", err, ErrConvertOverflow)
+	}
+	//--------------------
+	if _, err := Convert[uint](-1); !errors.Is(err, ErrConvertOverflow) {
+		t.Errorf("err = %v but should = %v", err, ErrConvertOverflow)
+	}
+	//--------------------
+	if result, err := Convert[uint8]("255"); err != nil || result != 255 {
+		t.Errorf("Convert[uint8] = %d, %v but should = %d", result, err, 255)
+	}
+	//--------------------
+	if _, err := Convert[uint16](65536); !errors.Is(err, ErrConvertOverflow) {
+		t.Errorf("err = %v but should = %v", err, ErrConvertOverflow)
+	}
+	//--------------------
+	if result, err := Convert[uint64]("18446744073709551615"); err != nil || result != math.MaxUint64 {
+		t.Errorf("Convert[uint64] = %d, %v but should = %d", result, err, uint64(math.MaxUint64))
+	}
+	//------------------------------------------------------------
+	if result, err := Convert[string](12.5); err != nil || result != "12.5" {
+		t.Errorf("Convert[string] = %q, %v but should = %q", result, err, "12.5")
+	}
+	//--------------------
+	if result, err := Convert[bool]("yes"); err != nil || !result {
+		t.Errorf("Convert[bool] = %v, %v but should = %v", result, err, true)
+	}
+	//--------------------
+	if result, err := Convert[float32]("0.5"); err != nil || result != 0.5 {
+		t.Errorf("Convert[float32] = %v, %v but should = %v", result, err, 0.5)
+	}
+	//--------------------
+	if result, err := Convert[time.Duration]("5m"); err != nil || result != 5*time.Minute {
+		t.Errorf("Convert[time.Duration] = %v, %v but should = %v", result, err, 5*time.Minute)
+	}
+	//--------------------
+	if result, err := Convert[time.Time]("1970-01-01T00:00:10Z"); err != nil || result.Unix() != 10 {
+		t.Errorf("Convert[time.Time] = %v, %v but should = %v", result, err, time.Unix(10, 0))
+	}
+	//------------------------------------------------------------
+	if _, err := Convert[string](struct{}{}); !errors.Is(err, ErrConvert) {
+		t.Errorf("err = %v but should = %v", err, ErrConvert)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
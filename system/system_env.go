@@ -0,0 +1,450 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/timbrockley/golang-main/file"
+)
+
+//------------------------------------------------------------
+
+// source reported by LoadENVsLayered for keys already set in the process environment
+const ENV_SOURCE_ENVIRONMENT = "environment"
+
+//------------------------------------------------------------
+// envPair => a parsed KEY=VALUE line
+//------------------------------------------------------------
+
+type envPair struct {
+	key         string
+	value       string
+	line        int
+	expand      bool // false for single quoted values
+	doubleQuote bool // backslash escapes are processed
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// FindENVFilenames => existing env files in path, least specific first
+//------------------------------------------------------------
+// .env, .docker.env, .<os>.env, .<hostname>.env, .<hostname>_<os>.env,
+// .<hostname>_docker.env (docker files only when running in docker)
+//------------------------------------------------------------
+
+func FindENVFilenames(path string) []string {
+	//------------------------------------------------------------
+	HOSTNAME := strings.ToLower(GetHostname())
+	//--------------------
+	dockerYesNo := file.FilePathExists("/.dockerenv")
+	//--------------------
+	OS := strings.ToLower(GetOS())
+	//------------------------------------------------------------
+	candidates := []string{".env"}
+	//--------------------
+	if dockerYesNo {
+		candidates = append(candidates, ".docker.env")
+	}
+	//--------------------
+	candidates = append(candidates, "."+OS+".env", "."+HOSTNAME+".env", "."+HOSTNAME+"_"+OS+".env")
+	//--------------------
+	if dockerYesNo {
+		candidates = append(candidates, "."+HOSTNAME+"_docker.env")
+	}
+	//------------------------------------------------------------
+	filenames := []string{}
+	//--------------------
+	for _, filename := range candidates {
+		if file.FilePathExists(file.FilePathJoin(path, filename)) {
+			filenames = append(filenames, filename)
+		}
+	}
+	//------------------------------------------------------------
+	return filenames
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// LoadENVsLayered => loads every file from FindENVFilenames in order,
+// later files overriding earlier ones, and returns the file each key came from
+//------------------------------------------------------------
+// values may use $VAR, ${VAR}, ${VAR:-default} (unset or blank) and
+// ${VAR-default} (unset), variables already set in the process environment
+// are never overridden (their source is ENV_SOURCE_ENVIRONMENT)
+//------------------------------------------------------------
+
+func LoadENVsLayered(Path ...string) (map[string]string, error) {
+	//------------------------------------------------------------
+	var path string
+	//------------------------------------------------------------
+	if Path != nil && Path[0] != "" {
+		//--------------------
+		path = Path[0]
+		//--------------------
+		if IsDirectory, _ := file.IsDirectory(path); !IsDirectory {
+			path = file.Path(path)
+		}
+		//--------------------
+	} else {
+		//--------------------
+		// runtime.Caller(0) => this script / runtime.Caller(1) => calling script
+		_, filePath, _, _ := runtime.Caller(1)
+		//--------------------
+		path = file.Path(filePath)
+		//--------------------
+	}
+	//------------------------------------------------------------
+	filenames := FindENVFilenames(path)
+	//--------------------
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("no env files found in %s", path)
+	}
+	//------------------------------------------------------------
+	values := map[string]string{}
+	sources := map[string]string{}
+	//------------------------------------------------------------
+	lookup := func(key string) (string, bool) {
+		if value, found := os.LookupEnv(key); found {
+			return value, true
+		}
+		value, found := values[key]
+		return value, found
+	}
+	//------------------------------------------------------------
+	for _, filename := range filenames {
+		//--------------------
+		filePath := file.FilePathJoin(path, filename)
+		//--------------------
+		dataBytes, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		//--------------------
+		pairs, err := parseENVData(string(dataBytes))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filePath, err)
+		}
+		//--------------------
+		for _, pair := range pairs {
+			//--------------------
+			value := pair.value
+			//--------------------
+			if pair.expand {
+				if value, err = expandENVValue(value, pair.doubleQuote, lookup); err != nil {
+					return nil, fmt.Errorf("%s:%d: %w", filePath, pair.line, err)
+				}
+			}
+			//--------------------
+			values[pair.key] = value
+			sources[pair.key] = filePath
+			//--------------------
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	for key, value := range values {
+		//--------------------
+		if _, found := os.LookupEnv(key); found {
+			sources[key] = ENV_SOURCE_ENVIRONMENT
+			continue
+		}
+		//--------------------
+		if err := os.Setenv(key, value); err != nil {
+			return nil, err
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return sources, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// parseENVData => [export] KEY=VALUE lines, # comments,
+// single quoted (literal) and double quoted (escaped, multi-line) values
+//------------------------------------------------------------
+
+func parseENVData(data string) ([]envPair, error) {
+	//------------------------------------------------------------
+	var pairs []envPair
+	//------------------------------------------------------------
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	//------------------------------------------------------------
+	line := 1
+	index := 0
+	//------------------------------------------------------------
+	for index < len(data) {
+		//------------------------------------------------------------
+		lineEnd := strings.IndexByte(data[index:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(data)
+		} else {
+			lineEnd += index
+		}
+		//--------------------
+		text := strings.TrimSpace(data[index:lineEnd])
+		//------------------------------------------------------------
+		if text == "" || text[0] == '#' {
+			index = lineEnd + 1
+			line++
+			continue
+		}
+		//------------------------------------------------------------
+		text = strings.TrimPrefix(text, "export ")
+		//--------------------
+		key, value, found := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		//--------------------
+		if !found || !isENVKey(key) {
+			return nil, fmt.Errorf("line %d: invalid line %q", line, text)
+		}
+		//------------------------------------------------------------
+		pair := envPair{key: key, line: line, expand: true}
+		//--------------------
+		value = strings.TrimLeft(value, " \t")
+		//------------------------------------------------------------
+		if value != "" && (value[0] == '\'' || value[0] == '"') {
+			//--------------------
+			quote := value[0]
+			//--------------------
+			// quoted values may continue over several lines
+			valueStart := strings.Index(data[index:], "=") + index + 1
+			valueStart += len(data[valueStart:]) - len(strings.TrimLeft(data[valueStart:], " \t")) + 1
+			//--------------------
+			valueEnd := valueStart
+			//--------------------
+			for ; valueEnd < len(data) && data[valueEnd] != quote; valueEnd++ {
+				if quote == '"' && data[valueEnd] == '\\' {
+					valueEnd++
+				}
+			}
+			//--------------------
+			if valueEnd >= len(data) {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", line)
+			}
+			//--------------------
+			pair.value = data[valueStart:valueEnd]
+			pair.expand = quote == '"'
+			pair.doubleQuote = quote == '"'
+			//--------------------
+			line += strings.Count(pair.value, "\n")
+			//--------------------
+			lineEnd = strings.IndexByte(data[valueEnd:], '\n')
+			if lineEnd < 0 {
+				lineEnd = len(data)
+			} else {
+				lineEnd += valueEnd
+			}
+			//--------------------
+			if rest := strings.TrimSpace(data[valueEnd+1 : lineEnd]); rest != "" && rest[0] != '#' {
+				return nil, fmt.Errorf("line %d: unexpected %q after quoted value", line, rest)
+			}
+			//--------------------
+		} else {
+			//--------------------
+			// inline comments need a space before the #
+			if commentIndex := strings.Index(value, " #"); commentIndex >= 0 {
+				value = value[:commentIndex]
+			}
+			//--------------------
+			pair.value = strings.TrimSpace(value)
+			//--------------------
+		}
+		//------------------------------------------------------------
+		pairs = append(pairs, pair)
+		//------------------------------------------------------------
+		index = lineEnd + 1
+		line++
+		//------------------------------------------------------------
+	}
+	//------------------------------------------------------------
+	return pairs, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// expandENVValue => expands $VAR, ${VAR}, ${VAR:-default} and ${VAR-default}
+//------------------------------------------------------------
+
+func expandENVValue(value string, escapes bool, lookup func(string) (string, bool)) (string, error) {
+	//------------------------------------------------------------
+	var builder strings.Builder
+	//------------------------------------------------------------
+	for index := 0; index < len(value); index++ {
+		//------------------------------------------------------------
+		char := value[index]
+		//------------------------------------------------------------
+		if char == '\\' && index+1 < len(value) {
+			//--------------------
+			next := value[index+1]
+			//--------------------
+			switch {
+			case next == '$':
+				builder.WriteByte('$')
+				index++
+				continue
+			case escapes && next == 'n':
+				builder.WriteByte('\n')
+				index++
+				continue
+			case escapes && next == 'r':
+				builder.WriteByte('\r')
+				index++
+				continue
+			case escapes && next == 't':
+				builder.WriteByte('\t')
+				index++
+				continue
+			case escapes && (next == '"' || next == '\\'):
+				builder.WriteByte(next)
+				index++
+				continue
+			}
+			//--------------------
+		}
+		//------------------------------------------------------------
+		if char != '$' || index+1 >= len(value) {
+			builder.WriteByte(char)
+			continue
+		}
+		//------------------------------------------------------------
+		if value[index+1] != '{' {
+			//--------------------
+			nameEnd := index + 1
+			//--------------------
+			for nameEnd < len(value) && isENVNameChar(value[nameEnd]) {
+				nameEnd++
+			}
+			//--------------------
+			if nameEnd == index+1 {
+				builder.WriteByte(char)
+				continue
+			}
+			//--------------------
+			envValue, _ := lookup(value[index+1 : nameEnd])
+			builder.WriteString(envValue)
+			//--------------------
+			index = nameEnd - 1
+			continue
+			//--------------------
+		}
+		//------------------------------------------------------------
+		// find the closing brace allowing for nested ${...} in defaults
+		depth := 0
+		braceEnd := -1
+		//--------------------
+		for scan := index + 1; scan < len(value); scan++ {
+			if value[scan] == '{' {
+				depth++
+			} else if value[scan] == '}' {
+				depth--
+				if depth == 0 {
+					braceEnd = scan
+					break
+				}
+			}
+		}
+		//--------------------
+		if braceEnd < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", value)
+		}
+		//------------------------------------------------------------
+		expression := value[index+2 : braceEnd]
+		//--------------------
+		nameEnd := 0
+		for nameEnd < len(expression) && isENVNameChar(expression[nameEnd]) {
+			nameEnd++
+		}
+		//--------------------
+		name := expression[:nameEnd]
+		operator := expression[nameEnd:]
+		//--------------------
+		if name == "" {
+			return "", fmt.Errorf("invalid variable ${%s}", expression)
+		}
+		//------------------------------------------------------------
+		envValue, found := lookup(name)
+		//------------------------------------------------------------
+		switch {
+		case operator == "":
+		case strings.HasPrefix(operator, ":-"):
+			if !found || envValue == "" {
+				defaultValue, err := expandENVValue(operator[2:], escapes, lookup)
+				if err != nil {
+					return "", err
+				}
+				envValue = defaultValue
+			}
+		case strings.HasPrefix(operator, "-"):
+			if !found {
+				defaultValue, err := expandENVValue(operator[1:], escapes, lookup)
+				if err != nil {
+					return "", err
+				}
+				envValue = defaultValue
+			}
+		default:
+			return "", fmt.Errorf("invalid variable ${%s}", expression)
+		}
+		//------------------------------------------------------------
+		builder.WriteString(envValue)
+		//--------------------
+		index = braceEnd
+		//------------------------------------------------------------
+	}
+	//------------------------------------------------------------
+	return builder.String(), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// isENVKey
+//------------------------------------------------------------
+
+func isENVKey(key string) bool {
+	//------------------------------------------------------------
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+	//------------------------------------------------------------
+	for index := 0; index < len(key); index++ {
+		if !isENVNameChar(key[index]) && key[index] != '.' && key[index] != '-' {
+			return false
+		}
+	}
+	//------------------------------------------------------------
+	return true
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// isENVNameChar
+//------------------------------------------------------------
+
+func isENVNameChar(char byte) bool {
+	//------------------------------------------------------------
+	return char == '_' || (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9')
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
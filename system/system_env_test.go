@@ -0,0 +1,156 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// LoadENVsLayered
+//------------------------------------------------------------
+
+func TestLoadENVsLayered(t *testing.T) {
+	//------------------------------------------------------------
+	tempPath := t.TempDir()
+	//--------------------
+	HOSTNAME := strings.ToLower(GetHostname())
+	OS := strings.ToLower(GetOS())
+	//------------------------------------------------------------
+	envFiles := map[string]string{
+		".env": "# base file\n" +
+			"export LAYTEST_NAME=base\n" +
+			"LAYTEST_HOST=localhost # inline comment\n" +
+			"LAYTEST_PORT=5432\n" +
+			"LAYTEST_URL=${LAYTEST_HOST}:${LAYTEST_PORT}/${LAYTEST_DB:-app}\n" +
+			"LAYTEST_LITERAL='$LAYTEST_HOST'\n" +
+			"LAYTEST_QUOTED=\"line1\\nline2 \\$HOME\"\n" +
+			"LAYTEST_MULTI=\"a\nb\"\n" +
+			"LAYTEST_EXISTING=file\n",
+		"." + OS + ".env":       "LAYTEST_PORT=6543\nLAYTEST_OS=$LAYTEST_NAME-os\n",
+		"." + HOSTNAME + ".env": "LAYTEST_NAME=host\nLAYTEST_EMPTY=\nLAYTEST_DEFAULT=${LAYTEST_EMPTY:-fallback}|${LAYTEST_EMPTY-kept}|${LAYTEST_UNSET-${LAYTEST_PORT}}\n",
+	}
+	//--------------------
+	for filename, data := range envFiles {
+		if err := os.WriteFile(filepath.Join(tempPath, filename), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//------------------------------------------------------------
+	expectedValues := map[string]string{
+		"LAYTEST_NAME":     "host",
+		"LAYTEST_HOST":     "localhost",
+		"LAYTEST_PORT":     "6543",
+		"LAYTEST_URL":      "localhost:5432/app",
+		"LAYTEST_LITERAL":  "$LAYTEST_HOST",
+		"LAYTEST_QUOTED":   "line1\nline2 $HOME",
+		"LAYTEST_MULTI":    "a\nb",
+		"LAYTEST_EXISTING": "environment",
+		"LAYTEST_OS":       "base-os",
+		"LAYTEST_EMPTY":    "",
+		"LAYTEST_DEFAULT":  "fallback||6543",
+	}
+	//------------------------------------------------------------
+	t.Setenv("LAYTEST_EXISTING", "environment")
+	//--------------------
+	// variables loaded from the files are removed after the test
+	for key := range expectedValues {
+		if key != "LAYTEST_EXISTING" {
+			t.Setenv(key, "")
+			os.Unsetenv(key)
+		}
+	}
+	//------------------------------------------------------------
+	sources, err := LoadENVsLayered(tempPath)
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	for key, expectedValue := range expectedValues {
+		if resultString := os.Getenv(key); resultString != expectedValue {
+			t.Errorf("%s = %q but should = %q", key, resultString, expectedValue)
+		}
+	}
+	//------------------------------------------------------------
+	expectedSources := map[string]string{
+		"LAYTEST_NAME":     filepath.Join(tempPath, "."+HOSTNAME+".env"),
+		"LAYTEST_HOST":     filepath.Join(tempPath, ".env"),
+		"LAYTEST_PORT":     filepath.Join(tempPath, "."+OS+".env"),
+		"LAYTEST_EXISTING": ENV_SOURCE_ENVIRONMENT,
+	}
+	//--------------------
+	for key, expectedSource := range expectedSources {
+		if sources[key] != expectedSource {
+			t.Errorf("sources[%s] = %q but should = %q", key, sources[key], expectedSource)
+		}
+	}
+	//------------------------------------------------------------
+	if _, err := LoadENVsLayered(t.TempDir()); err == nil {
+		t.Error("a path without env files should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// FindENVFilenames
+//------------------------------------------------------------
+
+func TestFindENVFilenames(t *testing.T) {
+	//------------------------------------------------------------
+	tempPath := t.TempDir()
+	//--------------------
+	HOSTNAME := strings.ToLower(GetHostname())
+	OS := strings.ToLower(GetOS())
+	//------------------------------------------------------------
+	expectedFilenames := []string{".env", "." + OS + ".env", "." + HOSTNAME + "_" + OS + ".env"}
+	//--------------------
+	for _, filename := range expectedFilenames {
+		if err := os.WriteFile(filepath.Join(tempPath, filename), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//------------------------------------------------------------
+	resultFilenames := FindENVFilenames(tempPath)
+	//--------------------
+	if strings.Join(resultFilenames, ",") != strings.Join(expectedFilenames, ",") {
+		t.Errorf("resultFilenames = %q but should = %q", resultFilenames, expectedFilenames)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// parseENVData errors
+//------------------------------------------------------------
+
+func TestParseENVDataErrors(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []string{
+		"NOT A LINE",
+		"1KEY=value",
+		"KEY=\"unterminated",
+		"KEY='value' trailing",
+	}
+	//------------------------------------------------------------
+	for _, dataString := range testCases {
+		if _, err := parseENVData(dataString); err == nil {
+			t.Errorf("parseENVData(%q) should return an error", dataString)
+		}
+	}
+	//------------------------------------------------------------
+	if _, err := expandENVValue("${UNTERMINATED", false, os.LookupEnv); err == nil {
+		t.Error("an unterminated ${ should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
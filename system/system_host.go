@@ -0,0 +1,381 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timbrockley/golang-main/file"
+)
+
+//------------------------------------------------------------
+
+var ErrHostInfoUnsupported = errors.New("host information not supported on this platform")
+
+//------------------------------------------------------------
+// HostInfo
+//------------------------------------------------------------
+
+type HostInfo struct {
+	Hostname string
+	OS       string
+	// logical CPUs usable by this process
+	CPUCount int
+	// bytes
+	MemoryTotal     uint64
+	MemoryAvailable uint64
+	// 1, 5 and 15 minute load averages
+	LoadAverage [3]float64
+	Uptime      time.Duration
+	Disk        DiskUsage
+	Process     ProcessInfo
+	Container   ContainerInfo
+}
+
+//------------------------------------------------------------
+// DiskUsage => bytes for the filesystem holding Path
+//------------------------------------------------------------
+
+type DiskUsage struct {
+	Path      string
+	Total     uint64
+	Free      uint64
+	Available uint64 // free space usable by unprivileged users
+	Used      uint64
+}
+
+//------------------------------------------------------------
+// ProcessInfo => the current process
+//------------------------------------------------------------
+
+type ProcessInfo struct {
+	PID        int
+	RSS        uint64 // resident set size in bytes
+	OpenFDs    int
+	Goroutines int
+}
+
+//------------------------------------------------------------
+// ContainerInfo
+//------------------------------------------------------------
+
+type ContainerInfo struct {
+	InContainer bool
+	// docker, podman, kubernetes, lxc or containerd ("container" if unknown)
+	Runtime string
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// GetHostInfo => host and process details read from /proc on Linux
+//------------------------------------------------------------
+// disk usage is for path (defaults to "/"), anything that cannot be read
+// is left empty and its error joined into the returned error
+//------------------------------------------------------------
+
+func GetHostInfo(path ...string) (HostInfo, error) {
+	//------------------------------------------------------------
+	diskPath := "/"
+	//--------------------
+	if len(path) > 0 && path[0] != "" {
+		diskPath = path[0]
+	}
+	//------------------------------------------------------------
+	var errs []error
+	//------------------------------------------------------------
+	hostInfo := HostInfo{
+		Hostname:  GetHostname(),
+		OS:        GetOS(),
+		CPUCount:  runtime.NumCPU(),
+		Container: GetContainerInfo(),
+	}
+	//------------------------------------------------------------
+	if runtime.GOOS != "linux" {
+		return hostInfo, ErrHostInfoUnsupported
+	}
+	//------------------------------------------------------------
+	if dataBytes, err := os.ReadFile("/proc/meminfo"); err == nil {
+		hostInfo.MemoryTotal, hostInfo.MemoryAvailable = parseMeminfo(string(dataBytes))
+	} else {
+		errs = append(errs, err)
+	}
+	//--------------------
+	if dataBytes, err := os.ReadFile("/proc/loadavg"); err == nil {
+		hostInfo.LoadAverage, err = parseLoadavg(string(dataBytes))
+		errs = append(errs, err)
+	} else {
+		errs = append(errs, err)
+	}
+	//--------------------
+	if dataBytes, err := os.ReadFile("/proc/uptime"); err == nil {
+		hostInfo.Uptime, err = parseUptime(string(dataBytes))
+		errs = append(errs, err)
+	} else {
+		errs = append(errs, err)
+	}
+	//------------------------------------------------------------
+	var err error
+	//--------------------
+	hostInfo.Disk, err = GetDiskUsage(diskPath)
+	errs = append(errs, err)
+	//--------------------
+	hostInfo.Process, err = GetProcessInfo()
+	errs = append(errs, err)
+	//------------------------------------------------------------
+	return hostInfo, errors.Join(errs...)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// GetProcessInfo => RSS and open file descriptors from /proc/self
+//------------------------------------------------------------
+
+func GetProcessInfo() (ProcessInfo, error) {
+	//------------------------------------------------------------
+	processInfo := ProcessInfo{PID: os.Getpid(), Goroutines: runtime.NumGoroutine()}
+	//------------------------------------------------------------
+	if runtime.GOOS != "linux" {
+		return processInfo, ErrHostInfoUnsupported
+	}
+	//------------------------------------------------------------
+	dataBytes, err := os.ReadFile("/proc/self/status")
+	//--------------------
+	if err != nil {
+		return processInfo, err
+	}
+	//--------------------
+	processInfo.RSS = parseProcStatusRSS(string(dataBytes))
+	//------------------------------------------------------------
+	entries, err := os.ReadDir("/proc/self/fd")
+	//--------------------
+	if err != nil {
+		return processInfo, err
+	}
+	//--------------------
+	// the directory being read holds one of the descriptors
+	processInfo.OpenFDs = max(len(entries)-1, 0)
+	//------------------------------------------------------------
+	return processInfo, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// GetContainerInfo => marker files, KUBERNETES_SERVICE_HOST and
+// the cgroup / mount tables of the current process
+//------------------------------------------------------------
+
+func GetContainerInfo() ContainerInfo {
+	//------------------------------------------------------------
+	switch {
+	case os.Getenv("KUBERNETES_SERVICE_HOST") != "":
+		return ContainerInfo{InContainer: true, Runtime: "kubernetes"}
+	case file.FilePathExists("/.dockerenv"):
+		return ContainerInfo{InContainer: true, Runtime: "docker"}
+	case file.FilePathExists("/run/.containerenv"):
+		return ContainerInfo{InContainer: true, Runtime: "podman"}
+	}
+	//------------------------------------------------------------
+	cgroupBytes, _ := os.ReadFile("/proc/self/cgroup")
+	mountinfoBytes, _ := os.ReadFile("/proc/self/mountinfo")
+	//------------------------------------------------------------
+	return detectContainer(string(cgroupBytes), string(mountinfoBytes))
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// detectContainer => runtime named in /proc/self/cgroup (cgroup v1)
+// or in the container paths of /proc/self/mountinfo (cgroup v2)
+//------------------------------------------------------------
+
+func detectContainer(cgroupData string, mountinfoData string) ContainerInfo {
+	//------------------------------------------------------------
+	runtimes := []struct{ marker, runtime string }{
+		{"kubepods", "kubernetes"},
+		{"libpod", "podman"},
+		{"docker", "docker"},
+		{"containerd", "containerd"},
+		{"lxc", "lxc"},
+	}
+	//------------------------------------------------------------
+	for _, line := range strings.Split(cgroupData, "\n") {
+		//--------------------
+		// hierarchy-ID:controllers:path
+		fields := strings.SplitN(line, ":", 3)
+		//--------------------
+		if len(fields) < 3 || fields[2] == "/" {
+			continue
+		}
+		//--------------------
+		for _, entry := range runtimes {
+			if strings.Contains(fields[2], entry.marker) {
+				return ContainerInfo{InContainer: true, Runtime: entry.runtime}
+			}
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	for _, line := range strings.Split(mountinfoData, "\n") {
+		//--------------------
+		fields := strings.Fields(line)
+		//--------------------
+		if len(fields) < 5 || (fields[4] != "/etc/hostname" && fields[4] != "/etc/resolv.conf") {
+			continue
+		}
+		//--------------------
+		// the source of these bind mounts is the runtime's container directory
+		for _, entry := range runtimes {
+			if strings.Contains(fields[3], "/"+entry.marker+"/") || strings.Contains(fields[3], "/"+entry.marker+"-") {
+				return ContainerInfo{InContainer: true, Runtime: entry.runtime}
+			}
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return ContainerInfo{}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// parseMeminfo => MemTotal and MemAvailable in bytes
+// (MemFree + Buffers + Cached on kernels without MemAvailable)
+//------------------------------------------------------------
+
+func parseMeminfo(data string) (uint64, uint64) {
+	//------------------------------------------------------------
+	values := map[string]uint64{}
+	//------------------------------------------------------------
+	for _, line := range strings.Split(data, "\n") {
+		//--------------------
+		// MemTotal:        6158152 kB
+		key, value, found := strings.Cut(line, ":")
+		//--------------------
+		if !found {
+			continue
+		}
+		//--------------------
+		fields := strings.Fields(value)
+		//--------------------
+		if len(fields) == 0 {
+			continue
+		}
+		//--------------------
+		kiloBytes, err := strconv.ParseUint(fields[0], 10, 64)
+		//--------------------
+		if err == nil {
+			values[key] = kiloBytes * 1024
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	available, found := values["MemAvailable"]
+	//--------------------
+	if !found {
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	//------------------------------------------------------------
+	return values["MemTotal"], available
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// parseLoadavg => 1, 5 and 15 minute load averages from /proc/loadavg
+//------------------------------------------------------------
+
+func parseLoadavg(data string) ([3]float64, error) {
+	//------------------------------------------------------------
+	var loadAverage [3]float64
+	//------------------------------------------------------------
+	fields := strings.Fields(data)
+	//--------------------
+	if len(fields) < 3 {
+		return loadAverage, fmt.Errorf("invalid loadavg %q", data)
+	}
+	//------------------------------------------------------------
+	for index := range loadAverage {
+		//--------------------
+		value, err := strconv.ParseFloat(fields[index], 64)
+		//--------------------
+		if err != nil {
+			return loadAverage, fmt.Errorf("invalid loadavg %q", data)
+		}
+		//--------------------
+		loadAverage[index] = value
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return loadAverage, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// parseUptime => first field of /proc/uptime (seconds)
+//------------------------------------------------------------
+
+func parseUptime(data string) (time.Duration, error) {
+	//------------------------------------------------------------
+	fields := strings.Fields(data)
+	//--------------------
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid uptime %q", data)
+	}
+	//------------------------------------------------------------
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	//--------------------
+	if err != nil {
+		return 0, fmt.Errorf("invalid uptime %q", data)
+	}
+	//------------------------------------------------------------
+	return time.Duration(seconds * float64(time.Second)), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// parseProcStatusRSS => VmRSS from /proc/self/status in bytes
+//------------------------------------------------------------
+
+func parseProcStatusRSS(data string) uint64 {
+	//------------------------------------------------------------
+	for _, line := range strings.Split(data, "\n") {
+		//--------------------
+		value, found := strings.CutPrefix(line, "VmRSS:")
+		//--------------------
+		if !found {
+			continue
+		}
+		//--------------------
+		fields := strings.Fields(value)
+		//--------------------
+		if len(fields) > 0 {
+			kiloBytes, _ := strconv.ParseUint(fields[0], 10, 64)
+			return kiloBytes * 1024
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return 0
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,47 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"syscall"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// GetDiskUsage => statfs for the filesystem holding path
+//------------------------------------------------------------
+
+func GetDiskUsage(path string) (DiskUsage, error) {
+	//------------------------------------------------------------
+	diskUsage := DiskUsage{Path: path}
+	//------------------------------------------------------------
+	var stat syscall.Statfs_t
+	//--------------------
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return diskUsage, err
+	}
+	//------------------------------------------------------------
+	blockSize := uint64(stat.Bsize)
+	//--------------------
+	diskUsage.Total = stat.Blocks * blockSize
+	diskUsage.Free = stat.Bfree * blockSize
+	diskUsage.Available = stat.Bavail * blockSize
+	diskUsage.Used = diskUsage.Total - diskUsage.Free
+	//------------------------------------------------------------
+	return diskUsage, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,30 @@
+//go:build !linux
+
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// GetDiskUsage => only supported on Linux
+//------------------------------------------------------------
+
+func GetDiskUsage(path string) (DiskUsage, error) {
+	//------------------------------------------------------------
+	return DiskUsage{Path: path}, ErrHostInfoUnsupported
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
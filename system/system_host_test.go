@@ -0,0 +1,146 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// GetHostInfo
+//------------------------------------------------------------
+
+func TestGetHostInfo(t *testing.T) {
+	//------------------------------------------------------------
+	if runtime.GOOS != "linux" {
+		t.Skip("host information is read from /proc on linux")
+	}
+	//------------------------------------------------------------
+	hostInfo, err := GetHostInfo(t.TempDir())
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	if hostInfo.Hostname != GetHostname() || hostInfo.OS != "linux" || hostInfo.CPUCount <= 0 {
+		t.Errorf("Hostname = %q, OS = %q, CPUCount = %d", hostInfo.Hostname, hostInfo.OS, hostInfo.CPUCount)
+	}
+	//--------------------
+	if hostInfo.MemoryTotal == 0 || hostInfo.MemoryAvailable == 0 || hostInfo.MemoryAvailable > hostInfo.MemoryTotal {
+		t.Errorf("MemoryTotal = %d, MemoryAvailable = %d", hostInfo.MemoryTotal, hostInfo.MemoryAvailable)
+	}
+	//--------------------
+	if hostInfo.Uptime <= 0 || hostInfo.LoadAverage[0] < 0 {
+		t.Errorf("Uptime = %v, LoadAverage = %v", hostInfo.Uptime, hostInfo.LoadAverage)
+	}
+	//--------------------
+	if hostInfo.Disk.Total == 0 || hostInfo.Disk.Used > hostInfo.Disk.Total || hostInfo.Disk.Available > hostInfo.Disk.Free {
+		t.Errorf("Disk = %+v", hostInfo.Disk)
+	}
+	//------------------------------------------------------------
+	if hostInfo.Process.PID != os.Getpid() || hostInfo.Process.RSS == 0 || hostInfo.Process.OpenFDs < 3 || hostInfo.Process.Goroutines <= 0 {
+		t.Errorf("Process = %+v", hostInfo.Process)
+	}
+	//------------------------------------------------------------
+	if _, err := GetDiskUsage("/path/does/not/exist"); err == nil {
+		t.Error("a missing path should return an error")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// detectContainer
+//------------------------------------------------------------
+
+func TestDetectContainer(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		cgroupData    string
+		mountinfoData string
+		expected      ContainerInfo
+	}{
+		{
+			"12:memory:/docker/3f2a9c\n0::/",
+			"",
+			ContainerInfo{InContainer: true, Runtime: "docker"},
+		},
+		{
+			"11:cpu:/kubepods/besteffort/pod1234/abcd\n",
+			"",
+			ContainerInfo{InContainer: true, Runtime: "kubernetes"},
+		},
+		{
+			"0::/",
+			"612 600 0:52 /var/lib/docker/containers/3f2a9c/hostname /etc/hostname rw,relatime - ext4 /dev/sda1 rw",
+			ContainerInfo{InContainer: true, Runtime: "docker"},
+		},
+		{
+			"0::/",
+			"612 600 0:52 /containers/storage/overlay-containers/libpod-3f2a/userdata/hostname /etc/hostname rw - tmpfs tmpfs rw",
+			ContainerInfo{InContainer: true, Runtime: "podman"},
+		},
+		{
+			"0::/user.slice/user-1000.slice/session-2.scope\n",
+			"22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw",
+			ContainerInfo{},
+		},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		if result := detectContainer(testCase.cgroupData, testCase.mountinfoData); result != testCase.expected {
+			t.Errorf("detectContainer(%q) = %+v but should = %+v", testCase.cgroupData, result, testCase.expected)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// proc parsers
+//------------------------------------------------------------
+
+func TestProcParsers(t *testing.T) {
+	//------------------------------------------------------------
+	total, available := parseMeminfo("MemTotal:        6158152 kB\nMemFree:         4804340 kB\nMemAvailable:    5658052 kB\n")
+	//--------------------
+	if total != 6158152*1024 || available != 5658052*1024 {
+		t.Errorf("total = %d, available = %d", total, available)
+	}
+	//--------------------
+	total, available = parseMeminfo("MemTotal: 1000 kB\nMemFree: 100 kB\nBuffers: 20 kB\nCached: 30 kB\n")
+	//--------------------
+	if total != 1000*1024 || available != 150*1024 {
+		t.Errorf("total = %d, available = %d", total, available)
+	}
+	//------------------------------------------------------------
+	loadAverage, err := parseLoadavg("0.09 0.08 1.50 2/71 15736\n")
+	//--------------------
+	if err != nil || loadAverage != [3]float64{0.09, 0.08, 1.5} {
+		t.Errorf("loadAverage = %v, err = %v", loadAverage, err)
+	}
+	//--------------------
+	if _, err := parseLoadavg("0.09"); err == nil {
+		t.Error("an invalid loadavg should return an error")
+	}
+	//------------------------------------------------------------
+	uptime, err := parseUptime("3655.50 3199.63\n")
+	//--------------------
+	if err != nil || uptime != 3655*time.Second+500*time.Millisecond {
+		t.Errorf("uptime = %v, err = %v", uptime, err)
+	}
+	//------------------------------------------------------------
+	if rss := parseProcStatusRSS("Name:\ttest\nVmRSS:\t    1796 kB\n"); rss != 1796*1024 {
+		t.Errorf("rss = %d but should = %d", rss, 1796*1024)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,232 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"reflect"
+)
+
+//------------------------------------------------------------
+
+type MergeStrategy int
+
+//------------------------------------------------------------
+
+const (
+	// values in src replace values in dst
+	MergeOverride MergeStrategy = iota
+	// as MergeOverride but slices in src are appended to slices in dst
+	MergeAppend
+	// values already in dst are kept, only missing keys are added from src
+	MergeKeep
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// DeepCopy => copies maps and slices recursively so nothing is shared with value
+//------------------------------------------------------------
+
+func DeepCopy(value any) any {
+	//------------------------------------------------------------
+	switch typedValue := value.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		return DeepCopyMap(typedValue)
+	case []any:
+		if typedValue == nil {
+			return typedValue
+		}
+		sliceCopy := make([]any, len(typedValue))
+		for index, elem := range typedValue {
+			sliceCopy[index] = DeepCopy(elem)
+		}
+		return sliceCopy
+	case []map[string]any:
+		if typedValue == nil {
+			return typedValue
+		}
+		sliceCopy := make([]map[string]any, len(typedValue))
+		for index, elem := range typedValue {
+			sliceCopy[index] = DeepCopyMap(elem)
+		}
+		return sliceCopy
+	}
+	//------------------------------------------------------------
+	// any other map or slice type ([]string, map[string]int, ...)
+	reflectValue := reflect.ValueOf(value)
+	//------------------------------------------------------------
+	switch reflectValue.Kind() {
+	case reflect.Map:
+		//--------------------
+		if reflectValue.IsNil() {
+			return value
+		}
+		//--------------------
+		mapCopy := reflect.MakeMapWithSize(reflectValue.Type(), reflectValue.Len())
+		iterator := reflectValue.MapRange()
+		//--------------------
+		for iterator.Next() {
+			mapCopy.SetMapIndex(iterator.Key(), deepCopyValue(iterator.Value(), reflectValue.Type().Elem()))
+		}
+		//--------------------
+		return mapCopy.Interface()
+		//--------------------
+	case reflect.Slice:
+		//--------------------
+		if reflectValue.IsNil() {
+			return value
+		}
+		//--------------------
+		sliceCopy := reflect.MakeSlice(reflectValue.Type(), reflectValue.Len(), reflectValue.Len())
+		//--------------------
+		for index := 0; index < reflectValue.Len(); index++ {
+			sliceCopy.Index(index).Set(deepCopyValue(reflectValue.Index(index), reflectValue.Type().Elem()))
+		}
+		//--------------------
+		return sliceCopy.Interface()
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return value
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// DeepCopyMap
+//------------------------------------------------------------
+
+func DeepCopyMap(dataMap map[string]any) map[string]any {
+	//------------------------------------------------------------
+	if dataMap == nil {
+		return nil
+	}
+	//------------------------------------------------------------
+	mapCopy := make(map[string]any, len(dataMap))
+	//--------------------
+	for key, value := range dataMap {
+		mapCopy[key] = DeepCopy(value)
+	}
+	//------------------------------------------------------------
+	return mapCopy
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// DeepMerge => merges src into a deep copy of dst (neither is modified)
+//------------------------------------------------------------
+// nested maps are always merged key by key, strategy (defaults to
+// MergeOverride) decides what happens to other values present in both
+//------------------------------------------------------------
+
+func DeepMerge(dst map[string]any, src map[string]any, strategy ...MergeStrategy) map[string]any {
+	//------------------------------------------------------------
+	mergeStrategy := MergeOverride
+	//--------------------
+	if len(strategy) > 0 {
+		mergeStrategy = strategy[0]
+	}
+	//------------------------------------------------------------
+	result := DeepCopyMap(dst)
+	//--------------------
+	if result == nil {
+		result = make(map[string]any, len(src))
+	}
+	//------------------------------------------------------------
+	for key, srcValue := range src {
+		//--------------------
+		dstValue, found := result[key]
+		//--------------------
+		if !found {
+			result[key] = DeepCopy(srcValue)
+			continue
+		}
+		//--------------------
+		dstMap, dstIsMap := dstValue.(map[string]any)
+		srcMap, srcIsMap := srcValue.(map[string]any)
+		//--------------------
+		if dstIsMap && srcIsMap {
+			result[key] = DeepMerge(dstMap, srcMap, mergeStrategy)
+			continue
+		}
+		//--------------------
+		switch mergeStrategy {
+		case MergeKeep:
+			continue
+		case MergeAppend:
+			if mergedSlice, ok := appendSlices(dstValue, srcValue); ok {
+				result[key] = mergedSlice
+				continue
+			}
+		}
+		//--------------------
+		result[key] = DeepCopy(srcValue)
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return result
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// deepCopyValue => DeepCopy for a reflect.Value stored as elemType
+//------------------------------------------------------------
+
+func deepCopyValue(reflectValue reflect.Value, elemType reflect.Type) reflect.Value {
+	//------------------------------------------------------------
+	valueCopy := DeepCopy(reflectValue.Interface())
+	//------------------------------------------------------------
+	if valueCopy == nil {
+		return reflect.Zero(elemType)
+	}
+	//------------------------------------------------------------
+	return reflect.ValueOf(valueCopy).Convert(elemType)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// appendSlices => copies of a and b concatenated (as []any if the types differ)
+//------------------------------------------------------------
+
+func appendSlices(a any, b any) (any, bool) {
+	//------------------------------------------------------------
+	aValue := reflect.ValueOf(DeepCopy(a))
+	bValue := reflect.ValueOf(DeepCopy(b))
+	//------------------------------------------------------------
+	if aValue.Kind() != reflect.Slice || bValue.Kind() != reflect.Slice {
+		return nil, false
+	}
+	//------------------------------------------------------------
+	if aValue.Type() == bValue.Type() {
+		return reflect.AppendSlice(aValue, bValue).Interface(), true
+	}
+	//------------------------------------------------------------
+	merged := make([]any, 0, aValue.Len()+bValue.Len())
+	//--------------------
+	for _, sliceValue := range []reflect.Value{aValue, bValue} {
+		for index := 0; index < sliceValue.Len(); index++ {
+			merged = append(merged, sliceValue.Index(index).Interface())
+		}
+	}
+	//------------------------------------------------------------
+	return merged, true
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
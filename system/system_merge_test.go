@@ -0,0 +1,133 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// DeepCopy
+//------------------------------------------------------------
+
+func TestDeepCopy(t *testing.T) {
+	//------------------------------------------------------------
+	original := map[string]any{
+		"map":     map[string]any{"list": []any{1, map[string]any{"a": "b"}}},
+		"strings": []string{"x", "y"},
+		"records": []map[string]any{{"id": 1}},
+		"ints":    map[string][]int{"a": {1, 2}},
+		"bytes":   []byte("abc"),
+		"nil":     nil,
+	}
+	//------------------------------------------------------------
+	expectedJSON, _ := json.Marshal(original)
+	//------------------------------------------------------------
+	copied := DeepCopy(original).(map[string]any)
+	//------------------------------------------------------------
+	copied["map"].(map[string]any)["list"].([]any)[1].(map[string]any)["a"] = "changed"
+	copied["map"].(map[string]any)["list"].([]any)[0] = 2
+	copied["strings"].([]string)[0] = "changed"
+	copied["records"].([]map[string]any)[0]["id"] = 2
+	copied["ints"].(map[string][]int)["a"][0] = 9
+	copied["bytes"].([]byte)[0] = 'z'
+	//------------------------------------------------------------
+	resultJSON, _ := json.Marshal(original)
+	//--------------------
+	if string(resultJSON) != string(expectedJSON) {
+		t.Errorf("original = %s but should = %s (copy is still aliased)", resultJSON, expectedJSON)
+	}
+	//------------------------------------------------------------
+	if _, found := copied["nil"]; !found || copied["nil"] != nil {
+		t.Errorf(`copied["nil"] = %v, %v but should = nil, true`, copied["nil"], found)
+	}
+	//------------------------------------------------------------
+	if DeepCopy(nil) != nil || DeepCopy(42) != 42 || DeepCopyMap(nil) != nil {
+		t.Error("scalars and nil should be returned unchanged")
+	}
+	//------------------------------------------------------------
+	copyMap := CopyMap(original)
+	copyMap["strings"].([]string)[1] = "changed"
+	//--------------------
+	if original["strings"].([]string)[1] != "y" {
+		t.Error("CopyMap should not share nested slices")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// DeepMerge
+//------------------------------------------------------------
+
+func TestDeepMerge(t *testing.T) {
+	//------------------------------------------------------------
+	base := map[string]any{
+		"name":  "base",
+		"debug": false,
+		"hosts": []any{"a"},
+		"db":    map[string]any{"host": "localhost", "port": 5432, "options": map[string]any{"ssl": false}},
+	}
+	//--------------------
+	layer := map[string]any{
+		"debug": true,
+		"hosts": []any{"b"},
+		"db":    map[string]any{"port": 6432, "options": map[string]any{"timeout": "5s"}},
+		"extra": []any{1},
+	}
+	//------------------------------------------------------------
+	baseJSON, _ := json.Marshal(base)
+	layerJSON, _ := json.Marshal(layer)
+	//------------------------------------------------------------
+	testCases := []struct {
+		strategy     MergeStrategy
+		expectedJSON string
+	}{
+		{MergeOverride, `{"db":{"host":"localhost","options":{"ssl":false,"timeout":"5s"},"port":6432},"debug":true,"extra":[1],"hosts":["b"],"name":"base"}`},
+		{MergeAppend, `{"db":{"host":"localhost","options":{"ssl":false,"timeout":"5s"},"port":6432},"debug":true,"extra":[1],"hosts":["a","b"],"name":"base"}`},
+		{MergeKeep, `{"db":{"host":"localhost","options":{"ssl":false,"timeout":"5s"},"port":5432},"debug":false,"extra":[1],"hosts":["a"],"name":"base"}`},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		merged := DeepMerge(base, layer, testCase.strategy)
+		//--------------------
+		resultJSON, _ := json.Marshal(merged)
+		//--------------------
+		if string(resultJSON) != testCase.expectedJSON {
+			t.Errorf("(%d) resultJSON = %s but should = %s", testCase.strategy, resultJSON, testCase.expectedJSON)
+		}
+		//--------------------
+		// the merged map must not share anything with its inputs
+		merged["db"].(map[string]any)["options"].(map[string]any)["ssl"] = true
+		merged["extra"].([]any)[0] = 2
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if resultJSON, _ := json.Marshal(base); string(resultJSON) != string(baseJSON) {
+		t.Errorf("base = %s but should = %s", resultJSON, baseJSON)
+	}
+	//--------------------
+	if resultJSON, _ := json.Marshal(layer); string(resultJSON) != string(layerJSON) {
+		t.Errorf("layer = %s but should = %s", resultJSON, layerJSON)
+	}
+	//------------------------------------------------------------
+	appended := DeepMerge(map[string]any{"list": []string{"a"}}, map[string]any{"list": []any{1}}, MergeAppend)
+	//--------------------
+	if resultJSON, _ := json.Marshal(appended); string(resultJSON) != `{"list":["a",1]}` {
+		t.Errorf("resultJSON = %s but should = %s", resultJSON, `{"list":["a",1]}`)
+	}
+	//------------------------------------------------------------
+	if merged := DeepMerge(nil, map[string]any{"a": 1}); merged["a"] != 1 {
+		t.Errorf("merged = %v but should = %v", merged, map[string]any{"a": 1})
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,367 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------
+
+var ErrNoOutboundIP = errors.New("no outbound ip address found")
+
+//------------------------------------------------------------
+
+const (
+	routeFlagUp     = 0x0001 // RTF_UP
+	routeFlagReject = 0x0200 // RTF_REJECT
+)
+
+//------------------------------------------------------------
+// NetAddress
+//------------------------------------------------------------
+
+type NetAddress struct {
+	IP        net.IP
+	PrefixLen int
+}
+
+//------------------------------------------------------------
+// NetInterface
+//------------------------------------------------------------
+
+type NetInterface struct {
+	Name      string
+	Index     int
+	MAC       string
+	Flags     net.Flags
+	MTU       int
+	Addresses []NetAddress
+}
+
+//------------------------------------------------------------
+// InterfaceFilter
+//------------------------------------------------------------
+
+type InterfaceFilter struct {
+	// skip loopback interfaces (lo)
+	ExcludeLoopback bool
+	// drop link-local addresses (169.254.0.0/16 and fe80::/10)
+	ExcludeLinkLocal bool
+	// skip docker bridges and virtual ethernet pairs (docker0, br-*, veth*)
+	ExcludeDocker bool
+	// skip interfaces that are not up
+	ExcludeDown bool
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// NetAddress - String => CIDR notation (192.168.1.2/24, fe80::1/64)
+//------------------------------------------------------------
+
+func (address NetAddress) String() string {
+	//------------------------------------------------------------
+	return address.IP.String() + "/" + strconv.Itoa(address.PrefixLen)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NetAddress - IsIPv4
+//------------------------------------------------------------
+
+func (address NetAddress) IsIPv4() bool {
+	//------------------------------------------------------------
+	return address.IP.To4() != nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NetAddress - IsIPv6
+//------------------------------------------------------------
+
+func (address NetAddress) IsIPv6() bool {
+	//------------------------------------------------------------
+	return address.IP.To4() == nil && address.IP.To16() != nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NetInterface - IsUp
+//------------------------------------------------------------
+
+func (netInterface NetInterface) IsUp() bool {
+	//------------------------------------------------------------
+	return netInterface.Flags&net.FlagUp != 0
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NetInterface - IsLoopback
+//------------------------------------------------------------
+
+func (netInterface NetInterface) IsLoopback() bool {
+	//------------------------------------------------------------
+	return netInterface.Flags&net.FlagLoopback != 0
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NetInterface - IsDocker => docker bridge or virtual ethernet pair
+//------------------------------------------------------------
+
+func (netInterface NetInterface) IsDocker() bool {
+	//------------------------------------------------------------
+	name := netInterface.Name
+	//------------------------------------------------------------
+	return strings.HasPrefix(name, "docker") || strings.HasPrefix(name, "br-") || strings.HasPrefix(name, "veth")
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// GetInterfaces => network interfaces with all IPv4 and IPv6 addresses
+//------------------------------------------------------------
+
+func GetInterfaces(filter ...InterfaceFilter) ([]NetInterface, error) {
+	//------------------------------------------------------------
+	var interfaceFilter InterfaceFilter
+	//--------------------
+	if len(filter) > 0 {
+		interfaceFilter = filter[0]
+	}
+	//------------------------------------------------------------
+	interfaces, err := net.Interfaces()
+	//--------------------
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	netInterfaces := []NetInterface{}
+	//------------------------------------------------------------
+	for _, iface := range interfaces {
+		//------------------------------------------------------------
+		netInterface := NetInterface{
+			Name:      iface.Name,
+			Index:     iface.Index,
+			MAC:       iface.HardwareAddr.String(),
+			Flags:     iface.Flags,
+			MTU:       iface.MTU,
+			Addresses: []NetAddress{},
+		}
+		//------------------------------------------------------------
+		if (interfaceFilter.ExcludeLoopback && netInterface.IsLoopback()) ||
+			(interfaceFilter.ExcludeDocker && netInterface.IsDocker()) ||
+			(interfaceFilter.ExcludeDown && !netInterface.IsUp()) {
+			continue
+		}
+		//------------------------------------------------------------
+		addresses, err := iface.Addrs()
+		//--------------------
+		if err != nil {
+			return nil, err
+		}
+		//------------------------------------------------------------
+		for _, addr := range addresses {
+			//--------------------
+			ipnet, ok := addr.(*net.IPNet)
+			//--------------------
+			if !ok {
+				continue
+			}
+			//--------------------
+			if interfaceFilter.ExcludeLinkLocal && (ipnet.IP.IsLinkLocalUnicast() || ipnet.IP.IsLinkLocalMulticast()) {
+				continue
+			}
+			//--------------------
+			prefixLen, _ := ipnet.Mask.Size()
+			//--------------------
+			netInterface.Addresses = append(netInterface.Addresses, NetAddress{IP: ipnet.IP, PrefixLen: prefixLen})
+			//--------------------
+		}
+		//------------------------------------------------------------
+		netInterfaces = append(netInterfaces, netInterface)
+		//------------------------------------------------------------
+	}
+	//------------------------------------------------------------
+	return netInterfaces, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// PreferredOutboundIP => address of the interface holding the default route
+//------------------------------------------------------------
+// reads /proc/net/route and /proc/net/ipv6_route on Linux, otherwise asks
+// the kernel which source address a UDP socket would use (no packets are
+// sent) and finally falls back to the first address from GetLocalIPs
+//------------------------------------------------------------
+
+func PreferredOutboundIP() (net.IP, error) {
+	//------------------------------------------------------------
+	netInterfaces, err := GetInterfaces(InterfaceFilter{ExcludeLoopback: true, ExcludeLinkLocal: true, ExcludeDown: true})
+	//--------------------
+	if err != nil {
+		return nil, err
+	}
+	//------------------------------------------------------------
+	for _, routeFile := range []string{"/proc/net/route", "/proc/net/ipv6_route"} {
+		//--------------------
+		dataBytes, err := os.ReadFile(routeFile)
+		//--------------------
+		if err != nil {
+			continue
+		}
+		//--------------------
+		var ifaceName string
+		//--------------------
+		if routeFile == "/proc/net/route" {
+			ifaceName = parseDefaultRoute(string(dataBytes))
+		} else {
+			ifaceName = parseDefaultRouteIPv6(string(dataBytes))
+		}
+		//--------------------
+		for _, netInterface := range netInterfaces {
+			//--------------------
+			if netInterface.Name != ifaceName {
+				continue
+			}
+			//--------------------
+			for _, address := range netInterface.Addresses {
+				if address.IsIPv4() == (routeFile == "/proc/net/route") {
+					return address.IP, nil
+				}
+			}
+			//--------------------
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	// connecting a UDP socket only selects a route
+	for _, network := range []string{"udp4", "udp6"} {
+		//--------------------
+		address := "192.0.2.1:9"
+		//--------------------
+		if network == "udp6" {
+			address = "[2001:db8::1]:9"
+		}
+		//--------------------
+		if conn, err := net.Dial(network, address); err == nil {
+			//--------------------
+			localAddr := conn.LocalAddr().(*net.UDPAddr)
+			conn.Close()
+			//--------------------
+			return localAddr.IP, nil
+			//--------------------
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if ipAddrs, _ := GetLocalIPs(); len(ipAddrs) > 0 {
+		return ipAddrs[0], nil
+	}
+	//------------------------------------------------------------
+	return nil, ErrNoOutboundIP
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// parseDefaultRoute => interface of the lowest metric default route in /proc/net/route
+//------------------------------------------------------------
+
+func parseDefaultRoute(data string) string {
+	//------------------------------------------------------------
+	ifaceName := ""
+	bestMetric := -1
+	//------------------------------------------------------------
+	for _, line := range strings.Split(data, "\n") {
+		//--------------------
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ...
+		fields := strings.Fields(line)
+		//--------------------
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		//--------------------
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&routeFlagUp == 0 || flags&routeFlagReject != 0 {
+			continue
+		}
+		//--------------------
+		metric, err := strconv.Atoi(fields[6])
+		if err != nil {
+			continue
+		}
+		//--------------------
+		if bestMetric < 0 || metric < bestMetric {
+			ifaceName = fields[0]
+			bestMetric = metric
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return ifaceName
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// parseDefaultRouteIPv6 => interface of the lowest metric default route in /proc/net/ipv6_route
+//------------------------------------------------------------
+
+func parseDefaultRouteIPv6(data string) string {
+	//------------------------------------------------------------
+	ifaceName := ""
+	bestMetric := uint64(0)
+	//------------------------------------------------------------
+	for _, line := range strings.Split(data, "\n") {
+		//--------------------
+		// Destination PrefixLen Source PrefixLen NextHop Metric RefCnt Use Flags Iface
+		fields := strings.Fields(line)
+		//--------------------
+		if len(fields) < 10 || fields[0] != strings.Repeat("0", 32) || fields[1] != "00" || fields[9] == "lo" {
+			continue
+		}
+		//--------------------
+		flags, err := strconv.ParseUint(fields[8], 16, 32)
+		if err != nil || flags&routeFlagUp == 0 || flags&routeFlagReject != 0 {
+			continue
+		}
+		//--------------------
+		metric, err := strconv.ParseUint(fields[5], 16, 32)
+		if err != nil {
+			continue
+		}
+		//--------------------
+		if ifaceName == "" || metric < bestMetric {
+			ifaceName = fields[9]
+			bestMetric = metric
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return ifaceName
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
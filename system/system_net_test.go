@@ -0,0 +1,153 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"net"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// GetInterfaces
+//------------------------------------------------------------
+
+func TestGetInterfaces(t *testing.T) {
+	//------------------------------------------------------------
+	netInterfaces, err := GetInterfaces()
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	loopbackYesNo := false
+	//--------------------
+	for _, netInterface := range netInterfaces {
+		//--------------------
+		if netInterface.Name == "" || netInterface.Index <= 0 {
+			t.Errorf("netInterface = %+v", netInterface)
+		}
+		//--------------------
+		for _, address := range netInterface.Addresses {
+			//--------------------
+			if address.IsIPv4() == address.IsIPv6() || address.PrefixLen <= 0 {
+				t.Errorf("%s: address = %s", netInterface.Name, address)
+			}
+			//--------------------
+			if netInterface.IsLoopback() && address.IP.IsLoopback() {
+				loopbackYesNo = true
+			}
+			//--------------------
+		}
+		//--------------------
+	}
+	//--------------------
+	if !loopbackYesNo {
+		t.Errorf("netInterfaces = %+v should include a loopback address", netInterfaces)
+	}
+	//------------------------------------------------------------
+	filtered, err := GetInterfaces(InterfaceFilter{ExcludeLoopback: true, ExcludeLinkLocal: true, ExcludeDocker: true})
+	//--------------------
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	for _, netInterface := range filtered {
+		//--------------------
+		if netInterface.IsLoopback() || netInterface.IsDocker() {
+			t.Errorf("%s should have been excluded", netInterface.Name)
+		}
+		//--------------------
+		for _, address := range netInterface.Addresses {
+			if address.IP.IsLinkLocalUnicast() {
+				t.Errorf("%s: link-local address %s should have been excluded", netInterface.Name, address)
+			}
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NetAddress - String
+//------------------------------------------------------------
+
+func TestNetAddressString(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		address  NetAddress
+		expected string
+		ipv4     bool
+	}{
+		{NetAddress{IP: net.ParseIP("192.168.1.2"), PrefixLen: 24}, "192.168.1.2/24", true},
+		{NetAddress{IP: net.ParseIP("fe80::1"), PrefixLen: 64}, "fe80::1/64", false},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		if resultString := testCase.address.String(); resultString != testCase.expected {
+			t.Errorf("resultString = %q but should = %q", resultString, testCase.expected)
+		}
+		//--------------------
+		if testCase.address.IsIPv4() != testCase.ipv4 || testCase.address.IsIPv6() == testCase.ipv4 {
+			t.Errorf("%s: IsIPv4 = %v but should = %v", testCase.expected, testCase.address.IsIPv4(), testCase.ipv4)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// parseDefaultRoute
+//------------------------------------------------------------
+
+func TestParseDefaultRoute(t *testing.T) {
+	//------------------------------------------------------------
+	routeData := "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+		"wlan0\t00000000\t0101A8C0\t0003\t0\t0\t600\t00000000\t0\t0\t0\n" +
+		"eth0\t00000000\t0100A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+		"eth0\t0000A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n" +
+		"tun0\t00000000\t00000000\t0200\t0\t0\t0\t00000000\t0\t0\t0\n"
+	//--------------------
+	if resultString := parseDefaultRoute(routeData); resultString != "eth0" {
+		t.Errorf("resultString = %q but should = %q", resultString, "eth0")
+	}
+	//------------------------------------------------------------
+	ipv6RouteData := "fd000000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0\n" +
+		"00000000000000000000000000000000 00 00000000000000000000000000000000 00 fd000000000000000000000000000001 00000400 00000001 00000000 00000003     eth0\n" +
+		"00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo\n"
+	//--------------------
+	if resultString := parseDefaultRouteIPv6(ipv6RouteData); resultString != "eth0" {
+		t.Errorf("resultString = %q but should = %q", resultString, "eth0")
+	}
+	//------------------------------------------------------------
+	if resultString := parseDefaultRoute(""); resultString != "" {
+		t.Errorf("resultString = %q but should = %q", resultString, "")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// PreferredOutboundIP
+//------------------------------------------------------------
+
+func TestPreferredOutboundIP(t *testing.T) {
+	//------------------------------------------------------------
+	ip, err := PreferredOutboundIP()
+	//------------------------------------------------------------
+	if err != nil {
+		t.Skip(err)
+	}
+	//--------------------
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() {
+		t.Errorf("ip = %v", ip)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,530 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------
+
+const (
+	OTP_TOTP = "totp"
+	OTP_HOTP = "hotp"
+	//--------------------
+	OTP_SHA1   = "SHA1"
+	OTP_SHA256 = "SHA256"
+	OTP_SHA512 = "SHA512"
+	//--------------------
+	OTP_DIGITS      = 6
+	OTP_PERIOD      = 30
+	OTP_SECRET_SIZE = 20
+)
+
+//------------------------------------------------------------
+
+var (
+	ErrOTPSecret       = errors.New("invalid secret")
+	ErrOTPSecretBase32 = errors.New("secret contains invalid base32 characters")
+	ErrOTPAlgorithm    = errors.New("invalid algorithm")
+	ErrOTPDigits       = errors.New("invalid digits")
+	ErrOTPPeriod       = errors.New("invalid period")
+	ErrOTPTime         = errors.New("invalid time")
+	ErrOTPType         = errors.New("invalid otp type")
+	ErrOTPURI          = errors.New("invalid otpauth uri")
+	ErrOTPInvalid      = errors.New("invalid otp code")
+	ErrOTPReplay       = errors.New("otp code already used")
+)
+
+//------------------------------------------------------------
+
+var otpBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+//------------------------------------------------------------
+// OTP => blank fields use the defaults (totp, SHA1, 6 digits, 30 seconds)
+//------------------------------------------------------------
+
+type OTP struct {
+	Type      string // OTP_TOTP or OTP_HOTP
+	Secret    string // base32 encoded (case-insensitive, padding optional)
+	Algorithm string // OTP_SHA1, OTP_SHA256 or OTP_SHA512
+	Digits    int
+	Period    int    // seconds (totp only)
+	Counter   uint64 // initial counter (hotp only, used in the otpauth uri)
+	Issuer    string
+	Account   string
+}
+
+//------------------------------------------------------------
+// OTPVerifyOptions
+//------------------------------------------------------------
+
+type OTPVerifyOptions struct {
+	// totp: time to verify against (defaults to time.Now)
+	Time time.Time
+	// hotp: next expected counter
+	Counter uint64
+	// totp: periods accepted either side of Time
+	// hotp: counters accepted after Counter (look-ahead window)
+	Skew int
+	// called with the matched counter before Verify succeeds,
+	// return an error (such as ErrOTPReplay) to reject a code that has already been used
+	Replay func(counter uint64) error
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// GenerateOTPSecret => random base32 secret (size in bytes, defaults to OTP_SECRET_SIZE)
+//------------------------------------------------------------
+
+func GenerateOTPSecret(size ...int) (string, error) {
+	//------------------------------------------------------------
+	secretSize := OTP_SECRET_SIZE
+	//--------------------
+	if len(size) > 0 && size[0] > 0 {
+		secretSize = size[0]
+	}
+	//------------------------------------------------------------
+	secretBytes := make([]byte, secretSize)
+	//--------------------
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return otpBase32.EncodeToString(secretBytes), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - HOTP => code for counter (RFC 4226)
+//------------------------------------------------------------
+
+func (otp OTP) HOTP(counter uint64) (string, error) {
+	//------------------------------------------------------------
+	hashFunc, err := otp.hashFunc()
+	if err != nil {
+		return "", err
+	}
+	//--------------------
+	digits, err := otp.digits()
+	if err != nil {
+		return "", err
+	}
+	//--------------------
+	key, err := otp.key()
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return otpCode(hashFunc, key, counter, digits), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - TOTP => code for time t (RFC 6238)
+//------------------------------------------------------------
+
+func (otp OTP) TOTP(t time.Time) (string, error) {
+	//------------------------------------------------------------
+	counter, err := otp.timeCounter(t)
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	return otp.HOTP(counter)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - Verify => returns the matched counter
+//------------------------------------------------------------
+
+func (otp OTP) Verify(code string, options OTPVerifyOptions) (uint64, error) {
+	//------------------------------------------------------------
+	hashFunc, err := otp.hashFunc()
+	if err != nil {
+		return 0, err
+	}
+	//--------------------
+	digits, err := otp.digits()
+	if err != nil {
+		return 0, err
+	}
+	//--------------------
+	key, err := otp.key()
+	if err != nil {
+		return 0, err
+	}
+	//------------------------------------------------------------
+	skew := uint64(max(options.Skew, 0))
+	//------------------------------------------------------------
+	var firstCounter, lastCounter uint64
+	//------------------------------------------------------------
+	switch otp.otpType() {
+	case OTP_TOTP:
+		//--------------------
+		t := options.Time
+		//--------------------
+		if t.IsZero() {
+			t = time.Now()
+		}
+		//--------------------
+		counter, err := otp.timeCounter(t)
+		if err != nil {
+			return 0, err
+		}
+		//--------------------
+		firstCounter = counter - min(counter, skew)
+		lastCounter = counter + min(skew, math.MaxUint64-counter)
+		//--------------------
+	case OTP_HOTP:
+		//--------------------
+		firstCounter = options.Counter
+		lastCounter = options.Counter + min(skew, math.MaxUint64-options.Counter)
+		//--------------------
+	default:
+		return 0, ErrOTPType
+	}
+	//------------------------------------------------------------
+	if len(code) != digits {
+		return 0, ErrOTPInvalid
+	}
+	//------------------------------------------------------------
+	// stops at lastCounter before incrementing so math.MaxUint64 cannot wrap to 0
+	for counter := firstCounter; ; counter++ {
+		//--------------------
+		if subtle.ConstantTimeCompare([]byte(otpCode(hashFunc, key, counter, digits)), []byte(code)) != 1 {
+			if counter == lastCounter {
+				break
+			}
+			continue
+		}
+		//--------------------
+		if options.Replay != nil {
+			if err := options.Replay(counter); err != nil {
+				return 0, err
+			}
+		}
+		//--------------------
+		return counter, nil
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return 0, ErrOTPInvalid
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - URI => otpauth://TYPE/ISSUER:ACCOUNT?secret=...
+//------------------------------------------------------------
+
+func (otp OTP) URI() (string, error) {
+	//------------------------------------------------------------
+	if _, err := otp.key(); err != nil {
+		return "", err
+	}
+	//--------------------
+	if _, err := otp.hashFunc(); err != nil {
+		return "", err
+	}
+	//--------------------
+	digits, err := otp.digits()
+	if err != nil {
+		return "", err
+	}
+	//------------------------------------------------------------
+	otpType := otp.otpType()
+	//------------------------------------------------------------
+	query := url.Values{}
+	query.Set("secret", strings.TrimRight(strings.ToUpper(otp.Secret), "="))
+	query.Set("algorithm", otp.algorithm())
+	query.Set("digits", strconv.Itoa(digits))
+	//------------------------------------------------------------
+	switch otpType {
+	case OTP_TOTP:
+		period, err := otp.period()
+		if err != nil {
+			return "", err
+		}
+		query.Set("period", strconv.Itoa(period))
+	case OTP_HOTP:
+		query.Set("counter", strconv.FormatUint(otp.Counter, 10))
+	default:
+		return "", ErrOTPType
+	}
+	//------------------------------------------------------------
+	label := otp.Account
+	//--------------------
+	if otp.Issuer != "" {
+		label = otp.Issuer + ":" + otp.Account
+		query.Set("issuer", otp.Issuer)
+	}
+	//------------------------------------------------------------
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     otpType,
+		Path:     "/" + label,
+		RawQuery: strings.ReplaceAll(query.Encode(), "+", "%20"),
+	}
+	//------------------------------------------------------------
+	return uri.String(), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ParseOTPURI => parses an otpauth:// uri
+//------------------------------------------------------------
+
+func ParseOTPURI(uriString string) (OTP, error) {
+	//------------------------------------------------------------
+	var otp OTP
+	//------------------------------------------------------------
+	uri, err := url.Parse(uriString)
+	if err != nil {
+		return OTP{}, fmt.Errorf("%w: %v", ErrOTPURI, err)
+	}
+	//--------------------
+	if uri.Scheme != "otpauth" {
+		return OTP{}, fmt.Errorf("%w: scheme must be otpauth", ErrOTPURI)
+	}
+	//------------------------------------------------------------
+	otp.Type = strings.ToLower(uri.Host)
+	//--------------------
+	if otp.Type != OTP_TOTP && otp.Type != OTP_HOTP {
+		return OTP{}, ErrOTPType
+	}
+	//------------------------------------------------------------
+	label := strings.TrimPrefix(uri.Path, "/")
+	//--------------------
+	if issuer, account, found := strings.Cut(label, ":"); found {
+		otp.Issuer = strings.TrimSpace(issuer)
+		otp.Account = strings.TrimSpace(account)
+	} else {
+		otp.Account = strings.TrimSpace(label)
+	}
+	//------------------------------------------------------------
+	query := uri.Query()
+	//------------------------------------------------------------
+	// the issuer parameter takes precedence over the label prefix
+	if query.Has("issuer") {
+		otp.Issuer = query.Get("issuer")
+	}
+	//------------------------------------------------------------
+	otp.Secret = query.Get("secret")
+	//--------------------
+	if _, err := otp.key(); err != nil {
+		return OTP{}, err
+	}
+	//------------------------------------------------------------
+	if query.Has("algorithm") {
+		otp.Algorithm = strings.ToUpper(query.Get("algorithm"))
+		if _, err := otp.hashFunc(); err != nil {
+			return OTP{}, err
+		}
+	}
+	//------------------------------------------------------------
+	if query.Has("digits") {
+		if otp.Digits, err = strconv.Atoi(query.Get("digits")); err != nil {
+			return OTP{}, ErrOTPDigits
+		}
+		if _, err := otp.digits(); err != nil {
+			return OTP{}, err
+		}
+	}
+	//------------------------------------------------------------
+	if query.Has("period") {
+		if otp.Period, err = strconv.Atoi(query.Get("period")); err != nil {
+			return OTP{}, ErrOTPPeriod
+		}
+		if _, err := otp.period(); err != nil {
+			return OTP{}, err
+		}
+	}
+	//------------------------------------------------------------
+	if otp.Type == OTP_HOTP {
+		if otp.Counter, err = strconv.ParseUint(query.Get("counter"), 10, 64); err != nil {
+			return OTP{}, fmt.Errorf("%w: invalid counter", ErrOTPURI)
+		}
+	}
+	//------------------------------------------------------------
+	return otp, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// otpCode => dynamic truncation (RFC 4226 section 5.3)
+//------------------------------------------------------------
+
+func otpCode(hashFunc func() hash.Hash, key []byte, counter uint64, digits int) string {
+	//------------------------------------------------------------
+	mac := hmac.New(hashFunc, key)
+	mac.Write(binary.BigEndian.AppendUint64(nil, counter))
+	hmacResult := mac.Sum(nil)
+	//------------------------------------------------------------
+	offset := hmacResult[len(hmacResult)-1] & 0xF
+	code := int64(binary.BigEndian.Uint32(hmacResult[offset:]) & 0x7FFFFFFF)
+	//------------------------------------------------------------
+	modulus := int64(1)
+	for i := 0; i < digits; i++ {
+		modulus *= 10
+	}
+	//------------------------------------------------------------
+	return fmt.Sprintf("%0*d", digits, code%modulus)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - otpType
+//------------------------------------------------------------
+
+func (otp OTP) otpType() string {
+	//------------------------------------------------------------
+	if otp.Type == "" {
+		return OTP_TOTP
+	}
+	//------------------------------------------------------------
+	return strings.ToLower(otp.Type)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - algorithm
+//------------------------------------------------------------
+
+func (otp OTP) algorithm() string {
+	//------------------------------------------------------------
+	if otp.Algorithm == "" {
+		return OTP_SHA1
+	}
+	//------------------------------------------------------------
+	return strings.ToUpper(otp.Algorithm)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - hashFunc
+//------------------------------------------------------------
+
+func (otp OTP) hashFunc() (func() hash.Hash, error) {
+	//------------------------------------------------------------
+	switch otp.algorithm() {
+	case OTP_SHA1:
+		return sha1.New, nil
+	case OTP_SHA256:
+		return sha256.New, nil
+	case OTP_SHA512:
+		return sha512.New, nil
+	}
+	//------------------------------------------------------------
+	return nil, ErrOTPAlgorithm
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - digits => 1 to 10 (the truncated value is 31 bits)
+//------------------------------------------------------------
+
+func (otp OTP) digits() (int, error) {
+	//------------------------------------------------------------
+	if otp.Digits == 0 {
+		return OTP_DIGITS, nil
+	}
+	//------------------------------------------------------------
+	if otp.Digits < 1 || otp.Digits > 10 {
+		return 0, ErrOTPDigits
+	}
+	//------------------------------------------------------------
+	return otp.Digits, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - period
+//------------------------------------------------------------
+
+func (otp OTP) period() (int, error) {
+	//------------------------------------------------------------
+	if otp.Period == 0 {
+		return OTP_PERIOD, nil
+	}
+	//------------------------------------------------------------
+	if otp.Period < 0 {
+		return 0, ErrOTPPeriod
+	}
+	//------------------------------------------------------------
+	return otp.Period, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - key => decoded secret
+//------------------------------------------------------------
+
+func (otp OTP) key() ([]byte, error) {
+	//------------------------------------------------------------
+	secret := strings.TrimRight(strings.ToUpper(strings.ReplaceAll(otp.Secret, " ", "")), "=")
+	//--------------------
+	if secret == "" {
+		return nil, ErrOTPSecret
+	}
+	//------------------------------------------------------------
+	key, err := otpBase32.DecodeString(secret)
+	if err != nil {
+		return nil, ErrOTPSecretBase32
+	}
+	//------------------------------------------------------------
+	return key, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - timeCounter => number of periods since the unix epoch
+//------------------------------------------------------------
+
+func (otp OTP) timeCounter(t time.Time) (uint64, error) {
+	//------------------------------------------------------------
+	period, err := otp.period()
+	if err != nil {
+		return 0, err
+	}
+	//------------------------------------------------------------
+	if t.Unix() < 0 {
+		return 0, ErrOTPTime
+	}
+	//------------------------------------------------------------
+	return uint64(t.Unix()) / uint64(period), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
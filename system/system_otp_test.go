@@ -0,0 +1,283 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// OTP - HOTP
+//------------------------------------------------------------
+
+func TestOTP_HOTP(t *testing.T) {
+	//------------------------------------------------------------
+	// RFC 4226 appendix D
+	otp := OTP{Type: OTP_HOTP, Secret: otpBase32.EncodeToString([]byte("12345678901234567890"))}
+	//------------------------------------------------------------
+	expectedCodes := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	//------------------------------------------------------------
+	for counter, expectedCode := range expectedCodes {
+		//--------------------
+		code, err := otp.HOTP(uint64(counter))
+		//--------------------
+		if err != nil {
+			t.Error(err)
+		} else if code != expectedCode {
+			t.Errorf("(%d) code = %q but should = %q", counter, code, expectedCode)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - TOTP
+//------------------------------------------------------------
+
+func TestOTP_TOTP(t *testing.T) {
+	//------------------------------------------------------------
+	// RFC 6238 appendix B
+	secrets := map[string]string{
+		OTP_SHA1:   otpBase32.EncodeToString([]byte("12345678901234567890")),
+		OTP_SHA256: otpBase32.EncodeToString([]byte("12345678901234567890123456789012")),
+		OTP_SHA512: otpBase32.EncodeToString([]byte("1234567890123456789012345678901234567890123456789012345678901234")),
+	}
+	//------------------------------------------------------------
+	testCases := []struct {
+		timestamp    int64
+		algorithm    string
+		expectedCode string
+	}{
+		{59, OTP_SHA1, "94287082"},
+		{59, OTP_SHA256, "46119246"},
+		{59, OTP_SHA512, "90693936"},
+		{1111111109, OTP_SHA1, "07081804"},
+		{1111111109, OTP_SHA256, "68084774"},
+		{1111111109, OTP_SHA512, "25091201"},
+		{1234567890, OTP_SHA1, "89005924"},
+		{20000000000, OTP_SHA1, "65353130"},
+		{20000000000, OTP_SHA256, "77737706"},
+		{20000000000, OTP_SHA512, "47863826"},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		otp := OTP{Secret: secrets[testCase.algorithm], Algorithm: testCase.algorithm, Digits: 8}
+		//--------------------
+		code, err := otp.TOTP(time.Unix(testCase.timestamp, 0))
+		//--------------------
+		if err != nil {
+			t.Errorf("(%d %s) %v", testCase.timestamp, testCase.algorithm, err)
+		} else if code != testCase.expectedCode {
+			t.Errorf("(%d %s) code = %q but should = %q", testCase.timestamp, testCase.algorithm, code, testCase.expectedCode)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	errorCases := []struct {
+		otp         OTP
+		expectedErr error
+	}{
+		{OTP{}, ErrOTPSecret},
+		{OTP{Secret: "INVALID!@#"}, ErrOTPSecretBase32},
+		{OTP{Secret: "JBSWY3DP", Algorithm: "MD5"}, ErrOTPAlgorithm},
+		{OTP{Secret: "JBSWY3DP", Digits: 11}, ErrOTPDigits},
+		{OTP{Secret: "JBSWY3DP", Period: -1}, ErrOTPPeriod},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range errorCases {
+		//--------------------
+		_, err := testCase.otp.TOTP(time.Now())
+		//--------------------
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("(%+v) err = %v but should = %v", testCase.otp, err, testCase.expectedErr)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - Verify
+//------------------------------------------------------------
+
+func TestOTP_Verify(t *testing.T) {
+	//------------------------------------------------------------
+	otp := OTP{Secret: "HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ"}
+	now := time.Unix(1478167454, 0)
+	//------------------------------------------------------------
+	previousCode, _ := otp.TOTP(now.Add(-30 * time.Second))
+	code, _ := otp.TOTP(now)
+	//------------------------------------------------------------
+	if counter, err := otp.Verify(code, OTPVerifyOptions{Time: now}); err != nil {
+		t.Error(err)
+	} else if counter != 1478167454/30 {
+		t.Errorf("counter = %d but should = %d", counter, 1478167454/30)
+	}
+	//------------------------------------------------------------
+	if _, err := otp.Verify(previousCode, OTPVerifyOptions{Time: now}); !errors.Is(err, ErrOTPInvalid) {
+		t.Errorf("err = %v but should = %v", err, ErrOTPInvalid)
+	}
+	//--------------------
+	if _, err := otp.Verify(previousCode, OTPVerifyOptions{Time: now, Skew: 1}); err != nil {
+		t.Error(err)
+	}
+	//--------------------
+	if _, err := otp.Verify("12345", OTPVerifyOptions{Time: now}); !errors.Is(err, ErrOTPInvalid) {
+		t.Errorf("err = %v but should = %v", err, ErrOTPInvalid)
+	}
+	//------------------------------------------------------------
+	usedCounters := map[uint64]bool{}
+	//--------------------
+	replay := func(counter uint64) error {
+		if usedCounters[counter] {
+			return ErrOTPReplay
+		}
+		usedCounters[counter] = true
+		return nil
+	}
+	//--------------------
+	if _, err := otp.Verify(code, OTPVerifyOptions{Time: now, Replay: replay}); err != nil {
+		t.Error(err)
+	}
+	//--------------------
+	if _, err := otp.Verify(code, OTPVerifyOptions{Time: now, Replay: replay}); !errors.Is(err, ErrOTPReplay) {
+		t.Errorf("err = %v but should = %v", err, ErrOTPReplay)
+	}
+	//------------------------------------------------------------
+	hotp := OTP{Type: OTP_HOTP, Secret: otp.Secret}
+	hotpCode, _ := hotp.HOTP(7)
+	//--------------------
+	if _, err := hotp.Verify(hotpCode, OTPVerifyOptions{Counter: 5}); !errors.Is(err, ErrOTPInvalid) {
+		t.Errorf("err = %v but should = %v", err, ErrOTPInvalid)
+	}
+	//--------------------
+	if counter, err := hotp.Verify(hotpCode, OTPVerifyOptions{Counter: 5, Skew: 2}); err != nil {
+		t.Error(err)
+	} else if counter != 7 {
+		t.Errorf("counter = %d but should = %d", counter, 7)
+	}
+	//------------------------------------------------------------
+	// the counter window must not wrap around at math.MaxUint64
+	maxCode, _ := hotp.HOTP(math.MaxUint64)
+	wrongCode := "000000"
+	//--------------------
+	if wrongCode == maxCode {
+		wrongCode = "111111"
+	}
+	//--------------------
+	if _, err := hotp.Verify(wrongCode, OTPVerifyOptions{Counter: math.MaxUint64, Skew: 2}); !errors.Is(err, ErrOTPInvalid) {
+		t.Errorf("err = %v but should = %v", err, ErrOTPInvalid)
+	}
+	//--------------------
+	if counter, err := hotp.Verify(maxCode, OTPVerifyOptions{Counter: math.MaxUint64 - 1, Skew: 5}); err != nil {
+		t.Error(err)
+	} else if counter != math.MaxUint64 {
+		t.Errorf("counter = %d but should = %d", counter, uint64(math.MaxUint64))
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// OTP - URI / ParseOTPURI
+//------------------------------------------------------------
+
+func TestOTP_URI(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		otp         OTP
+		expectedURI string
+	}{
+		{
+			OTP{Secret: "jbswy3dpehpk3pxp", Issuer: "Example Co", Account: "alice@example.com"},
+			"otpauth://totp/Example%20Co:alice@example.com?algorithm=SHA1&digits=6&issuer=Example%20Co&period=30&secret=JBSWY3DPEHPK3PXP",
+		},
+		{
+			OTP{Type: OTP_HOTP, Secret: "JBSWY3DPEHPK3PXP", Algorithm: OTP_SHA256, Digits: 8, Counter: 42, Account: "bob"},
+			"otpauth://hotp/bob?algorithm=SHA256&counter=42&digits=8&secret=JBSWY3DPEHPK3PXP",
+		},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		uri, err := testCase.otp.URI()
+		//--------------------
+		if err != nil {
+			t.Error(err)
+			continue
+		} else if uri != testCase.expectedURI {
+			t.Errorf("uri = %q but should = %q", uri, testCase.expectedURI)
+		}
+		//--------------------
+		otp, err := ParseOTPURI(uri)
+		//--------------------
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		//--------------------
+		code1, _ := otp.HOTP(1)
+		code2, _ := testCase.otp.HOTP(1)
+		//--------------------
+		if otp.Issuer != testCase.otp.Issuer || otp.Account != testCase.otp.Account || otp.Counter != testCase.otp.Counter || code1 != code2 {
+			t.Errorf("otp = %+v but should = %+v", otp, testCase.otp)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	errorURIs := []string{
+		"https://totp/alice?secret=JBSWY3DP",
+		"otpauth://motp/alice?secret=JBSWY3DP",
+		"otpauth://totp/alice",
+		"otpauth://totp/alice?secret=JBSWY3DP&algorithm=MD5",
+		"otpauth://totp/alice?secret=JBSWY3DP&digits=x",
+		"otpauth://hotp/alice?secret=JBSWY3DP",
+	}
+	//------------------------------------------------------------
+	for _, uri := range errorURIs {
+		if _, err := ParseOTPURI(uri); err == nil {
+			t.Errorf("(%s) should return an error", uri)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// GenerateOTPSecret
+//------------------------------------------------------------
+
+func TestGenerateOTPSecret(t *testing.T) {
+	//------------------------------------------------------------
+	secret, err := GenerateOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	if len(secret) != 32 || strings.Trim(secret, "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567") != "" {
+		t.Errorf("secret = %q should be 32 base32 characters", secret)
+	}
+	//------------------------------------------------------------
+	secret2, _ := GenerateOTPSecret(10)
+	//--------------------
+	if len(secret2) != 16 {
+		t.Errorf("len(secret) = %d but should = %d", len(secret2), 16)
+	}
+	//------------------------------------------------------------
+	if _, err := (OTP{Secret: secret}).TOTP(time.Now()); err != nil {
+		t.Error(err)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
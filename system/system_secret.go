@@ -0,0 +1,136 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+//------------------------------------------------------------
+
+// value shown in place of secrets by RedactENVs and GetENVsRedacted
+const REDACTED_VALUE = "********"
+
+//------------------------------------------------------------
+
+// case-insensitive path.Match patterns for variable names holding secrets
+var SecretPatterns = []string{
+	"*PWD*",
+	"*PASSWD*",
+	"*PASSWORD*",
+	"*SECRET*",
+	"*TOKEN*",
+	"*CREDENTIAL*",
+	"*PRIVATE*",
+	"*API_KEY*",
+	"*_KEY",
+	"*DSN*",
+}
+
+//------------------------------------------------------------
+
+var ErrENVFileConflict = errors.New("both variable and _FILE variable are set")
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// IsSecretENV => key matches one of patterns (defaults to SecretPatterns)
+//------------------------------------------------------------
+
+func IsSecretENV(key string, patterns ...string) bool {
+	//------------------------------------------------------------
+	if len(patterns) == 0 {
+		patterns = SecretPatterns
+	}
+	//------------------------------------------------------------
+	key = strings.ToUpper(key)
+	//------------------------------------------------------------
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToUpper(pattern), key); matched {
+			return true
+		}
+	}
+	//------------------------------------------------------------
+	return false
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// RedactENVs => copy of envs with secret values replaced by REDACTED_VALUE
+//------------------------------------------------------------
+
+func RedactENVs(envs map[string]string, patterns ...string) map[string]string {
+	//------------------------------------------------------------
+	redacted := make(map[string]string, len(envs))
+	//------------------------------------------------------------
+	for key, value := range envs {
+		//--------------------
+		if IsSecretENV(key, patterns...) {
+			value = REDACTED_VALUE
+		}
+		//--------------------
+		redacted[key] = value
+		//--------------------
+	}
+	//------------------------------------------------------------
+	return redacted
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// GetENVsRedacted => GetENVs safe for logging and debug output
+//------------------------------------------------------------
+
+func GetENVsRedacted(patterns ...string) map[string]string {
+	//------------------------------------------------------------
+	return RedactENVs(GetENVs(), patterns...)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// GetENVOrFile => value of key or, when key is not set, the contents
+// of the file named by key_FILE (Docker secrets style)
+//------------------------------------------------------------
+// trailing line breaks are removed from the file contents and
+// setting both key and key_FILE returns ErrENVFileConflict
+//------------------------------------------------------------
+
+func GetENVOrFile(key string) (string, error) {
+	//------------------------------------------------------------
+	value, found := os.LookupEnv(key)
+	filePath, fileFound := os.LookupEnv(key + "_FILE")
+	//------------------------------------------------------------
+	if !fileFound || filePath == "" {
+		return value, nil
+	}
+	//------------------------------------------------------------
+	if found && value != "" {
+		return "", fmt.Errorf("%s and %s_FILE: %w", key, key, ErrENVFileConflict)
+	}
+	//------------------------------------------------------------
+	dataBytes, err := os.ReadFile(filePath)
+	//--------------------
+	if err != nil {
+		return "", fmt.Errorf("%s_FILE: %w", key, err)
+	}
+	//------------------------------------------------------------
+	return strings.TrimRight(string(dataBytes), "\r\n"), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
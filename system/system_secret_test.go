@@ -0,0 +1,120 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// IsSecretENV
+//------------------------------------------------------------
+
+func TestIsSecretENV(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		key      string
+		patterns []string
+		expected bool
+	}{
+		{"MYSQL_PWD", nil, true},
+		{"POSTGRES_PWD", nil, true},
+		{"db_password", nil, true},
+		{"GITHUB_TOKEN", nil, true},
+		{"SIGNING_KEY", nil, true},
+		{"MYSQL_HOST", nil, false},
+		{"KEYBOARD", nil, false},
+		{"PATH", nil, false},
+		{"MYSQL_HOST", []string{"mysql_*"}, true},
+		{"MYSQL_PWD", []string{"*_HOST"}, false},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		if result := IsSecretENV(testCase.key, testCase.patterns...); result != testCase.expected {
+			t.Errorf("IsSecretENV(%q, %q) = %v but should = %v", testCase.key, testCase.patterns, result, testCase.expected)
+		}
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// GetENVsRedacted
+//------------------------------------------------------------
+
+func TestGetENVsRedacted(t *testing.T) {
+	//------------------------------------------------------------
+	t.Setenv("REDACTTEST_PWD", "secret")
+	t.Setenv("REDACTTEST_HOST", "localhost")
+	//------------------------------------------------------------
+	envs := GetENVsRedacted()
+	//--------------------
+	if envs["REDACTTEST_PWD"] != REDACTED_VALUE || envs["REDACTTEST_HOST"] != "localhost" {
+		t.Errorf("envs = %q, %q but should = %q, %q", envs["REDACTTEST_PWD"], envs["REDACTTEST_HOST"], REDACTED_VALUE, "localhost")
+	}
+	//------------------------------------------------------------
+	envs = GetENVsRedacted("REDACTTEST_HOST")
+	//--------------------
+	if envs["REDACTTEST_PWD"] != "secret" || envs["REDACTTEST_HOST"] != REDACTED_VALUE {
+		t.Errorf("envs = %q, %q but should = %q, %q", envs["REDACTTEST_PWD"], envs["REDACTTEST_HOST"], "secret", REDACTED_VALUE)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// GetENVOrFile
+//------------------------------------------------------------
+
+func TestGetENVOrFile(t *testing.T) {
+	//------------------------------------------------------------
+	secretPath := filepath.Join(t.TempDir(), "secret")
+	//--------------------
+	if err := os.WriteFile(secretPath, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	t.Setenv("FILETEST_PLAIN", "plain")
+	t.Setenv("FILETEST_PWD_FILE", secretPath)
+	t.Setenv("FILETEST_BOTH", "value")
+	t.Setenv("FILETEST_BOTH_FILE", secretPath)
+	t.Setenv("FILETEST_MISSING_FILE", secretPath+".missing")
+	//------------------------------------------------------------
+	testCases := []struct {
+		key      string
+		expected string
+		err      bool
+	}{
+		{"FILETEST_PLAIN", "plain", false},
+		{"FILETEST_PWD", "from-file", false},
+		{"FILETEST_UNSET", "", false},
+		{"FILETEST_BOTH", "", true},
+		{"FILETEST_MISSING", "", true},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		resultString, err := GetENVOrFile(testCase.key)
+		//--------------------
+		if (err != nil) != testCase.err {
+			t.Errorf("GetENVOrFile(%q) err = %v", testCase.key, err)
+		} else if resultString != testCase.expected {
+			t.Errorf("resultString = %q but should = %q", resultString, testCase.expected)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if _, err := GetENVOrFile("FILETEST_BOTH"); !errors.Is(err, ErrENVFileConflict) {
+		t.Errorf("err = %v but should = %v", err, ErrENVFileConflict)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -7,8 +7,8 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"regexp"
 	"testing"
+	"time"
 )
 
 //------------------------------------------------------------
@@ -277,6 +277,69 @@ func TestToBool(t *testing.T) {
 	//------------------------------------------------------------
 }
 
+//------------------------------------------------------------
+// ToTime
+//------------------------------------------------------------
+
+func TestToTime(t *testing.T) {
+	//------------------------------------------------------------
+	expectedTime := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	//------------------------------------------------------------
+	testCases := []any{
+		"2024-03-01T12:30:45Z",
+		"2024-03-01 12:30:45",
+		[]byte("2024-03-01 12:30:45.123456+00"),
+		int64(1709296245),
+		int64(1709296245000),
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		timeVal := ToTime(testCase)
+		//--------------------
+		if !timeVal.Truncate(time.Second).Equal(expectedTime) {
+			t.Errorf("ToTime(%#v) = %v but should = %v", testCase, timeVal, expectedTime)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if timeVal := ToTime("not a time"); !timeVal.IsZero() {
+		t.Errorf("ToTime(\"not a time\") = %v but should be zero", timeVal)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ToDuration
+//------------------------------------------------------------
+
+func TestToDuration(t *testing.T) {
+	//------------------------------------------------------------
+	testCases := []struct {
+		value    any
+		expected time.Duration
+	}{
+		{"5m", 5 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"90", 90 * time.Second},
+		{30, 30 * time.Second},
+		{2.5, 2500 * time.Millisecond},
+		{"five minutes", 0},
+		{nil, 0},
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		duration := ToDuration(testCase.value)
+		//--------------------
+		if duration != testCase.expected {
+			t.Errorf("ToDuration(%#v) = %v but should = %v", testCase.value, duration, testCase.expected)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+}
+
 //------------------------------------------------------------
 // ToStringSlice
 //------------------------------------------------------------
@@ -601,18 +664,3 @@ func TestGenerateOTP(t *testing.T) {
 //------------------------------------------------------------
 //############################################################
 //------------------------------------------------------------
-
-func UUIDIsValid(uuidString string) bool {
-	//------------------------------------------------------------
-	match, err := regexp.MatchString(`^[0-9a-fA-F]{8}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{12}$`, uuidString)
-	if err != nil {
-		return false
-	}
-	//------------------------------------------------------------
-	return match
-	//------------------------------------------------------------
-}
-
-//------------------------------------------------------------
-//############################################################
-//------------------------------------------------------------
@@ -0,0 +1,332 @@
+/*
+
+Copyright 2023-2024, Tim Brockley. All rights reserved.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+*/
+
+package system
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+//------------------------------------------------------------
+
+// UUID => RFC 9562 universally unique identifier
+type UUID [16]byte
+
+//------------------------------------------------------------
+
+var NilUUID UUID
+
+//------------------------------------------------------------
+
+var ErrUUIDInvalid = errors.New("invalid uuid")
+
+//------------------------------------------------------------
+
+// last v7 timestamp (milliseconds << 12 | sub-millisecond sequence)
+var uuidV7Mutex sync.Mutex
+var uuidV7Last uint64
+
+//------------------------------------------------------------
+
+// 100 nanosecond intervals between 1582-10-15 and 1970-01-01 (v1 and v6 timestamps)
+const uuidGregorianOffset = 122192928000000000
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// NewUUIDv4 => random UUID
+//------------------------------------------------------------
+
+func NewUUIDv4() (UUID, error) {
+	//------------------------------------------------------------
+	var uuid UUID
+	//------------------------------------------------------------
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return NilUUID, err
+	}
+	//------------------------------------------------------------
+	uuid.setVersion(4)
+	//------------------------------------------------------------
+	return uuid, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NewUUIDv7 => time-ordered UUID (sorts by creation time)
+//------------------------------------------------------------
+// the 12 bits after the millisecond timestamp hold a sequence
+// so UUIDs created by this process are strictly increasing
+//------------------------------------------------------------
+
+func NewUUIDv7() (UUID, error) {
+	//------------------------------------------------------------
+	var uuid UUID
+	//------------------------------------------------------------
+	if _, err := rand.Read(uuid[8:]); err != nil {
+		return NilUUID, err
+	}
+	//------------------------------------------------------------
+	now := time.Now()
+	// sub-millisecond fraction scaled to 12 bits
+	timestamp := uint64(now.UnixMilli())<<12 | uint64(now.Nanosecond()%1e6)*4096/1e6
+	//------------------------------------------------------------
+	uuidV7Mutex.Lock()
+	//--------------------
+	if timestamp <= uuidV7Last {
+		timestamp = uuidV7Last + 1
+	}
+	uuidV7Last = timestamp
+	//--------------------
+	uuidV7Mutex.Unlock()
+	//------------------------------------------------------------
+	binary.BigEndian.PutUint64(uuid[:8], (timestamp>>12)<<16|(timestamp&0x0FFF))
+	//------------------------------------------------------------
+	uuid.setVersion(7)
+	//------------------------------------------------------------
+	return uuid, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ParseUUID => accepts the standard form, optionally wrapped in braces
+// or prefixed with urn:uuid:, or 32 hex digits without hyphens
+//------------------------------------------------------------
+
+func ParseUUID(uuidString string) (UUID, error) {
+	//------------------------------------------------------------
+	var uuid UUID
+	//------------------------------------------------------------
+	text := uuidString
+	//--------------------
+	if len(text) == 45 && strings.EqualFold(text[:9], "urn:uuid:") {
+		text = text[9:]
+	} else if len(text) == 38 && text[0] == '{' && text[37] == '}' {
+		text = text[1:37]
+	}
+	//------------------------------------------------------------
+	switch len(text) {
+	case 36:
+		//--------------------
+		if text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+			return NilUUID, fmt.Errorf("%w: %q", ErrUUIDInvalid, uuidString)
+		}
+		//--------------------
+		text = text[0:8] + text[9:13] + text[14:18] + text[19:23] + text[24:]
+		//--------------------
+	case 32:
+	default:
+		return NilUUID, fmt.Errorf("%w: %q", ErrUUIDInvalid, uuidString)
+	}
+	//------------------------------------------------------------
+	if _, err := hex.Decode(uuid[:], []byte(text)); err != nil {
+		return NilUUID, fmt.Errorf("%w: %q", ErrUUIDInvalid, uuidString)
+	}
+	//------------------------------------------------------------
+	return uuid, nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUIDIsValid => true if uuidString is in the standard 8-4-4-4-12 form
+//------------------------------------------------------------
+
+func UUIDIsValid(uuidString string) bool {
+	//------------------------------------------------------------
+	if len(uuidString) != 36 {
+		return false
+	}
+	//------------------------------------------------------------
+	_, err := ParseUUID(uuidString)
+	//------------------------------------------------------------
+	return err == nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// UUID - String => lower case 8-4-4-4-12 form
+//------------------------------------------------------------
+
+func (uuid UUID) String() string {
+	//------------------------------------------------------------
+	buffer := make([]byte, 36)
+	//------------------------------------------------------------
+	hex.Encode(buffer[0:8], uuid[0:4])
+	buffer[8] = '-'
+	hex.Encode(buffer[9:13], uuid[4:6])
+	buffer[13] = '-'
+	hex.Encode(buffer[14:18], uuid[6:8])
+	buffer[18] = '-'
+	hex.Encode(buffer[19:23], uuid[8:10])
+	buffer[23] = '-'
+	hex.Encode(buffer[24:], uuid[10:])
+	//------------------------------------------------------------
+	return string(buffer)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - Bytes
+//------------------------------------------------------------
+
+func (uuid UUID) Bytes() []byte {
+	//------------------------------------------------------------
+	return append([]byte{}, uuid[:]...)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - Version
+//------------------------------------------------------------
+
+func (uuid UUID) Version() int {
+	//------------------------------------------------------------
+	return int(uuid[6] >> 4)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - IsNil
+//------------------------------------------------------------
+
+func (uuid UUID) IsNil() bool {
+	//------------------------------------------------------------
+	return uuid == NilUUID
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - Time => creation time for v1, v6 and v7 (zero time for other versions)
+//------------------------------------------------------------
+
+func (uuid UUID) Time() time.Time {
+	//------------------------------------------------------------
+	var intervals uint64
+	//------------------------------------------------------------
+	switch uuid.Version() {
+	case 7:
+		//--------------------
+		milliseconds := binary.BigEndian.Uint64(uuid[:8]) >> 16
+		//--------------------
+		return time.UnixMilli(int64(milliseconds))
+		//--------------------
+	case 1:
+		//--------------------
+		timeLow := uint64(binary.BigEndian.Uint32(uuid[0:4]))
+		timeMid := uint64(binary.BigEndian.Uint16(uuid[4:6]))
+		timeHigh := uint64(binary.BigEndian.Uint16(uuid[6:8]) & 0x0FFF)
+		//--------------------
+		intervals = timeHigh<<48 | timeMid<<32 | timeLow
+		//--------------------
+	case 6:
+		//--------------------
+		timeHigh := uint64(binary.BigEndian.Uint32(uuid[0:4]))
+		timeMid := uint64(binary.BigEndian.Uint16(uuid[4:6]))
+		timeLow := uint64(binary.BigEndian.Uint16(uuid[6:8]) & 0x0FFF)
+		//--------------------
+		intervals = timeHigh<<28 | timeMid<<12 | timeLow
+		//--------------------
+	default:
+		return time.Time{}
+	}
+	//------------------------------------------------------------
+	nanoseconds := (int64(intervals) - uuidGregorianOffset) * 100
+	//------------------------------------------------------------
+	return time.Unix(0, nanoseconds)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - MarshalText
+//------------------------------------------------------------
+
+func (uuid UUID) MarshalText() ([]byte, error) {
+	//------------------------------------------------------------
+	return []byte(uuid.String()), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - UnmarshalText
+//------------------------------------------------------------
+
+func (uuid *UUID) UnmarshalText(text []byte) error {
+	//------------------------------------------------------------
+	parsedUUID, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	//------------------------------------------------------------
+	*uuid = parsedUUID
+	//------------------------------------------------------------
+	return nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - Scan => implements sql.Scanner (text, 16 raw bytes or NULL)
+//------------------------------------------------------------
+
+func (uuid *UUID) Scan(src any) error {
+	//------------------------------------------------------------
+	switch typedValue := src.(type) {
+	case nil:
+		*uuid = NilUUID
+		return nil
+	case string:
+		return uuid.UnmarshalText([]byte(typedValue))
+	case []byte:
+		if len(typedValue) == len(uuid) {
+			copy(uuid[:], typedValue)
+			return nil
+		}
+		return uuid.UnmarshalText(typedValue)
+	}
+	//------------------------------------------------------------
+	return fmt.Errorf("%w: cannot scan %T", ErrUUIDInvalid, src)
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - Value => implements driver.Valuer (stored as text)
+//------------------------------------------------------------
+
+func (uuid UUID) Value() (driver.Value, error) {
+	//------------------------------------------------------------
+	return uuid.String(), nil
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - setVersion => sets the version and RFC 9562 variant bits
+//------------------------------------------------------------
+
+func (uuid *UUID) setVersion(version byte) {
+	//------------------------------------------------------------
+	uuid[6] = (uuid[6] & 0x0F) | version<<4
+	uuid[8] = (uuid[8] & 0x3F) | 0x80
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
@@ -0,0 +1,211 @@
+//------------------------------------------------------------
+
+package system
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------
+
+//------------------------------------------------------------
+// NewUUIDv4
+//------------------------------------------------------------
+
+func TestNewUUIDv4(t *testing.T) {
+	//------------------------------------------------------------
+	uuid1, err := NewUUIDv4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	//--------------------
+	uuid2, _ := NewUUIDv4()
+	//------------------------------------------------------------
+	if uuid1 == uuid2 {
+		t.Errorf("uuid1 = %s should not = uuid2", uuid1)
+	}
+	//------------------------------------------------------------
+	if uuid1.Version() != 4 {
+		t.Errorf("Version() = %d but should = %d", uuid1.Version(), 4)
+	}
+	//--------------------
+	if uuid1[8]&0xC0 != 0x80 {
+		t.Errorf("variant bits = %02x but should = %02x", uuid1[8]&0xC0, 0x80)
+	}
+	//--------------------
+	if !UUIDIsValid(uuid1.String()) {
+		t.Errorf("UUIDIsValid(%q) = false but should = true", uuid1.String())
+	}
+	//--------------------
+	if !uuid1.Time().IsZero() {
+		t.Errorf("Time() = %v but should be zero", uuid1.Time())
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// NewUUIDv7
+//------------------------------------------------------------
+
+func TestNewUUIDv7(t *testing.T) {
+	//------------------------------------------------------------
+	startTime := time.Now().Truncate(time.Millisecond)
+	//------------------------------------------------------------
+	previousUUID, err := NewUUIDv7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	//------------------------------------------------------------
+	for i := 0; i < 10000; i++ {
+		//--------------------
+		uuid, _ := NewUUIDv7()
+		//--------------------
+		if uuid.String() <= previousUUID.String() {
+			t.Fatalf("uuid = %s should sort after %s", uuid, previousUUID)
+		}
+		//--------------------
+		previousUUID = uuid
+		//--------------------
+	}
+	//------------------------------------------------------------
+	if previousUUID.Version() != 7 {
+		t.Errorf("Version() = %d but should = %d", previousUUID.Version(), 7)
+	}
+	//--------------------
+	if previousUUID[8]&0xC0 != 0x80 {
+		t.Errorf("variant bits = %02x but should = %02x", previousUUID[8]&0xC0, 0x80)
+	}
+	//------------------------------------------------------------
+	uuidTime := previousUUID.Time()
+	//--------------------
+	if uuidTime.Before(startTime) || uuidTime.After(time.Now().Add(time.Second)) {
+		t.Errorf("Time() = %v but should be close to %v", uuidTime, startTime)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// ParseUUID
+//------------------------------------------------------------
+
+func TestParseUUID(t *testing.T) {
+	//------------------------------------------------------------
+	expectedString := "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"
+	//------------------------------------------------------------
+	testCases := []string{
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+		"017F22E2-79B0-7CC3-98C4-DC0C0C07398F",
+		"{017f22e2-79b0-7cc3-98c4-dc0c0c07398f}",
+		"urn:uuid:017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+		"017f22e279b07cc398c4dc0c0c07398f",
+	}
+	//------------------------------------------------------------
+	for _, testCase := range testCases {
+		//--------------------
+		uuid, err := ParseUUID(testCase)
+		//--------------------
+		if err != nil {
+			t.Errorf("(%s) %v", testCase, err)
+		} else if uuid.String() != expectedString {
+			t.Errorf("(%s) resultString = %q but should = %q", testCase, uuid.String(), expectedString)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	// RFC 9562 appendix A.6 example (Tuesday, February 22, 2022 2:22:22.00 PM GMT-05:00)
+	uuid, _ := ParseUUID(expectedString)
+	expectedTime := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	//--------------------
+	if uuid.Version() != 7 || !uuid.Time().Equal(expectedTime) {
+		t.Errorf("Version() = %d, Time() = %v but should = 7, %v", uuid.Version(), uuid.Time().UTC(), expectedTime)
+	}
+	//------------------------------------------------------------
+	// RFC 9562 appendix A.1 (v1) and A.5 (v6) examples share the same timestamp
+	expectedTime = time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	//--------------------
+	for _, uuidString := range []string{"c232ab00-9414-11ec-b3c8-9f6bdeced846", "1ec9414c-232a-6b00-b3c8-9f6bdeced846"} {
+		//--------------------
+		uuid, _ := ParseUUID(uuidString)
+		//--------------------
+		if !uuid.Time().Equal(expectedTime) {
+			t.Errorf("(%s) Time() = %v but should = %v", uuidString, uuid.Time().UTC(), expectedTime)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	errorCases := []string{
+		"",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398",
+		"017f22e2x79b0-7cc3-98c4-dc0c0c07398f",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398g",
+		"{017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+	}
+	//------------------------------------------------------------
+	for _, testCase := range errorCases {
+		if _, err := ParseUUID(testCase); !errors.Is(err, ErrUUIDInvalid) {
+			t.Errorf("(%s) err = %v but should = %v", testCase, err, ErrUUIDInvalid)
+		}
+	}
+	//------------------------------------------------------------
+	if UUIDIsValid("017f22e279b07cc398c4dc0c0c07398f") {
+		t.Errorf("UUIDIsValid should only accept the standard form")
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+// UUID - Scan / Value / MarshalText
+//------------------------------------------------------------
+
+func TestUUID_ScanValue(t *testing.T) {
+	//------------------------------------------------------------
+	uuid, _ := NewUUIDv7()
+	//------------------------------------------------------------
+	value, err := uuid.Value()
+	//--------------------
+	if err != nil || value != uuid.String() {
+		t.Errorf("Value() = %v, %v but should = %q", value, err, uuid.String())
+	}
+	//------------------------------------------------------------
+	for _, src := range []any{uuid.String(), []byte(uuid.String()), uuid.Bytes()} {
+		//--------------------
+		var scannedUUID UUID
+		//--------------------
+		if err := scannedUUID.Scan(src); err != nil {
+			t.Errorf("(%T) %v", src, err)
+		} else if scannedUUID != uuid {
+			t.Errorf("(%T) uuid = %s but should = %s", src, scannedUUID, uuid)
+		}
+		//--------------------
+	}
+	//------------------------------------------------------------
+	scannedUUID := uuid
+	//--------------------
+	if err := scannedUUID.Scan(nil); err != nil || !scannedUUID.IsNil() {
+		t.Errorf("Scan(nil) = %s, %v but should = %s", scannedUUID, err, NilUUID)
+	}
+	//--------------------
+	if err := scannedUUID.Scan(42); !errors.Is(err, ErrUUIDInvalid) {
+		t.Errorf("err = %v but should = %v", err, ErrUUIDInvalid)
+	}
+	//------------------------------------------------------------
+	jsonBytes, _ := json.Marshal(map[string]UUID{"id": uuid})
+	//--------------------
+	var jsonMap map[string]UUID
+	//--------------------
+	if err := json.Unmarshal(jsonBytes, &jsonMap); err != nil {
+		t.Error(err)
+	} else if jsonMap["id"] != uuid {
+		t.Errorf("uuid = %s but should = %s (%s)", jsonMap["id"], uuid, jsonBytes)
+	}
+	//------------------------------------------------------------
+}
+
+//------------------------------------------------------------
+//############################################################
+//------------------------------------------------------------